@@ -0,0 +1,594 @@
+// Code generated by sharedlib/bindgen from sharedlib/exports.json. DO NOT EDIT.
+//
+// Typed koffi bindings of the lighter-go signer shared library.
+// Strings returned by the library are copied & freed by the wrappers, and errors are thrown as LighterError.
+
+import koffi from "koffi";
+
+export const LIGHTER_RESULT_VERSION = 1;
+export const LIGHTER_OK = 0;
+export const LIGHTER_ERR_INVALID_HANDLE = 1;
+export const LIGHTER_ERR_INVALID_REQUEST = 2;
+export const LIGHTER_ERR_TX = 3;
+export const LIGHTER_ERR_PANIC = 4;
+
+export class LighterError extends Error {
+  constructor(
+    message: string,
+    public readonly code: number = 0,
+  ) {
+    super(message);
+  }
+}
+
+const StrOrErr = koffi.struct("StrOrErr", { str: "void *", err: "void *" });
+
+const ApiKeyResponse = koffi.struct("ApiKeyResponse", { privateKey: "void *", publicKey: "void *", err: "void *" });
+
+export interface ApiKeyResponseValue {
+  privateKey: string | null;
+  publicKey: string | null;
+}
+
+const HandleOrErr = koffi.struct("HandleOrErr", { handle: "int64", err: "void *" });
+
+const LighterResult = koffi.struct("LighterResult", { version: "int", errCode: "int", txInfo: "void *", txHash: "void *", messageToSign: "void *", err: "void *" });
+
+export interface LighterResultValue {
+  version: number;
+  txInfo: string | null;
+  txHash: string | null;
+  messageToSign: string | null;
+}
+
+export class LighterSigner {
+  private readonly fns: Record<string, koffi.KoffiFunction>;
+
+  constructor(path: string) {
+    const lib = koffi.load(path);
+    this.fns = {
+      FreeString: lib.func("FreeString", "void", ["void *"]),
+      FreeStrOrErr: lib.func("FreeStrOrErr", "void", [StrOrErr]),
+      FreeApiKeyResponse: lib.func("FreeApiKeyResponse", "void", [ApiKeyResponse]),
+      FreeHandleOrErr: lib.func("FreeHandleOrErr", "void", [HandleOrErr]),
+      FreeLighterResult: lib.func("FreeLighterResult", "void", [LighterResult]),
+      OutstandingAllocations: lib.func("OutstandingAllocations", "int64", []),
+      GenerateAPIKey: lib.func("GenerateAPIKey", ApiKeyResponse, ["str"]),
+      CreateClientHandle: lib.func("CreateClientHandle", HandleOrErr, ["str", "str", "int", "int", "int64"]),
+      DestroyClientHandle: lib.func("DestroyClientHandle", "void *", ["int64"]),
+      CheckClientHandle: lib.func("CheckClientHandle", "void *", ["int64"]),
+      CreateClient: lib.func("CreateClient", "void *", ["str", "str", "int", "int", "int64"]),
+      CheckClient: lib.func("CheckClient", "void *", ["int", "int64"]),
+      SwitchAPIKey: lib.func("SwitchAPIKey", "void *", ["int"]),
+      DerivePublicKey: lib.func("DerivePublicKey", StrOrErr, ["str"]),
+      ComputeTxHash: lib.func("ComputeTxHash", StrOrErr, ["int", "str", "int"]),
+      VerifyTxSignature: lib.func("VerifyTxSignature", "void *", ["int", "str", "str", "int"]),
+      GetL1SignatureBody: lib.func("GetL1SignatureBody", StrOrErr, ["int", "str"]),
+      ParseAuthToken: lib.func("ParseAuthToken", StrOrErr, ["str"]),
+      VerifyAuthToken: lib.func("VerifyAuthToken", "void *", ["str", "str"]),
+      SignTx: lib.func("SignTx", LighterResult, ["int64", "int", "str"]),
+      SignChangePubKeyWithHandle: lib.func("SignChangePubKeyWithHandle", StrOrErr, ["int64", "str", "int64"]),
+      SignCreateOrderWithHandle: lib.func("SignCreateOrderWithHandle", StrOrErr, ["int64", "int", "int64", "int64", "int", "int", "int", "int", "int", "int", "int64", "int64"]),
+      SignCancelOrderWithHandle: lib.func("SignCancelOrderWithHandle", StrOrErr, ["int64", "int", "int64", "int64"]),
+      SignWithdrawWithHandle: lib.func("SignWithdrawWithHandle", StrOrErr, ["int64", "int64", "int64"]),
+      SignCreateSubAccountWithHandle: lib.func("SignCreateSubAccountWithHandle", StrOrErr, ["int64", "int64"]),
+      SignCancelAllOrdersWithHandle: lib.func("SignCancelAllOrdersWithHandle", StrOrErr, ["int64", "int", "int64", "int64"]),
+      SignModifyOrderWithHandle: lib.func("SignModifyOrderWithHandle", StrOrErr, ["int64", "int", "int64", "int64", "int64", "int64", "int64"]),
+      SignTransferWithHandle: lib.func("SignTransferWithHandle", StrOrErr, ["int64", "int64", "int64", "int64", "str", "int64"]),
+      SignCreatePublicPoolWithHandle: lib.func("SignCreatePublicPoolWithHandle", StrOrErr, ["int64", "int64", "int64", "int64", "int64"]),
+      SignUpdatePublicPoolWithHandle: lib.func("SignUpdatePublicPoolWithHandle", StrOrErr, ["int64", "int64", "int", "int64", "int64", "int64"]),
+      SignMintSharesWithHandle: lib.func("SignMintSharesWithHandle", StrOrErr, ["int64", "int64", "int64", "int64"]),
+      SignBurnSharesWithHandle: lib.func("SignBurnSharesWithHandle", StrOrErr, ["int64", "int64", "int64", "int64"]),
+      SignUpdateLeverageWithHandle: lib.func("SignUpdateLeverageWithHandle", StrOrErr, ["int64", "int", "int", "int", "int64"]),
+      SignUpdateMarginWithHandle: lib.func("SignUpdateMarginWithHandle", StrOrErr, ["int64", "int", "int64", "int", "int64"]),
+      CreateAuthTokenWithHandle: lib.func("CreateAuthTokenWithHandle", StrOrErr, ["int64", "int64"]),
+      SignChangePubKey: lib.func("SignChangePubKey", StrOrErr, ["str", "int64"]),
+      SignCreateOrder: lib.func("SignCreateOrder", StrOrErr, ["int", "int64", "int64", "int", "int", "int", "int", "int", "int", "int64", "int64"]),
+      SignCancelOrder: lib.func("SignCancelOrder", StrOrErr, ["int", "int64", "int64"]),
+      SignWithdraw: lib.func("SignWithdraw", StrOrErr, ["int64", "int64"]),
+      SignCreateSubAccount: lib.func("SignCreateSubAccount", StrOrErr, ["int64"]),
+      SignCancelAllOrders: lib.func("SignCancelAllOrders", StrOrErr, ["int", "int64", "int64"]),
+      SignModifyOrder: lib.func("SignModifyOrder", StrOrErr, ["int", "int64", "int64", "int64", "int64", "int64"]),
+      SignTransfer: lib.func("SignTransfer", StrOrErr, ["int64", "int64", "int64", "str", "int64"]),
+      SignCreatePublicPool: lib.func("SignCreatePublicPool", StrOrErr, ["int64", "int64", "int64", "int64"]),
+      SignUpdatePublicPool: lib.func("SignUpdatePublicPool", StrOrErr, ["int64", "int", "int64", "int64", "int64"]),
+      SignMintShares: lib.func("SignMintShares", StrOrErr, ["int64", "int64", "int64"]),
+      SignBurnShares: lib.func("SignBurnShares", StrOrErr, ["int64", "int64", "int64"]),
+      SignUpdateLeverage: lib.func("SignUpdateLeverage", StrOrErr, ["int", "int", "int", "int64"]),
+      SignUpdateMargin: lib.func("SignUpdateMargin", StrOrErr, ["int", "int64", "int", "int64"]),
+      CreateAuthToken: lib.func("CreateAuthToken", StrOrErr, ["int64"]),
+    };
+  }
+
+  private str(ptr: unknown): string | null {
+    return ptr === null ? null : (koffi.decode(ptr, "char", -1) as string);
+  }
+
+  private check(ptr: unknown): void {
+    if (ptr !== null) {
+      const message = this.str(ptr) as string;
+      this.fns.FreeString(ptr);
+      throw new LighterError(message);
+    }
+  }
+
+  /** OutstandingAllocations returns how many strings returned by the library were not freed yet */
+  outstandingAllocations(): number {
+    return this.fns.OutstandingAllocations();
+  }
+
+  generateAPIKey(seed: string): ApiKeyResponseValue {
+    const result = this.fns.GenerateAPIKey(seed);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return { privateKey: this.str(result.privateKey), publicKey: this.str(result.publicKey) };
+    } finally {
+      this.fns.FreeApiKeyResponse(result);
+    }
+  }
+
+  createClientHandle(url: string, privateKey: string, chainId: number, apiKeyIndex: number, accountIndex: number): number {
+    const result = this.fns.CreateClientHandle(url, privateKey, chainId, apiKeyIndex, accountIndex);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return result.handle;
+    } finally {
+      this.fns.FreeHandleOrErr(result);
+    }
+  }
+
+  destroyClientHandle(handle: number): void {
+    this.check(this.fns.DestroyClientHandle(handle));
+  }
+
+  checkClientHandle(handle: number): void {
+    this.check(this.fns.CheckClientHandle(handle));
+  }
+
+  createClient(url: string, privateKey: string, chainId: number, apiKeyIndex: number, accountIndex: number): void {
+    this.check(this.fns.CreateClient(url, privateKey, chainId, apiKeyIndex, accountIndex));
+  }
+
+  checkClient(apiKeyIndex: number, accountIndex: number): void {
+    this.check(this.fns.CheckClient(apiKeyIndex, accountIndex));
+  }
+
+  switchAPIKey(apiKeyIndex: number): void {
+    this.check(this.fns.SwitchAPIKey(apiKeyIndex));
+  }
+
+  derivePublicKey(privateKey: string): string {
+    const result = this.fns.DerivePublicKey(privateKey);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  computeTxHash(txType: number, txInfo: string, chainId: number): string {
+    const result = this.fns.ComputeTxHash(txType, txInfo, chainId);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  /** VerifyTxSignature returns NULL when valid, or the reason it's invalid */
+  verifyTxSignature(txType: number, txInfo: string, pubKey: string, chainId: number): void {
+    this.check(this.fns.VerifyTxSignature(txType, txInfo, pubKey, chainId));
+  }
+
+  getL1SignatureBody(txType: number, txInfo: string): string {
+    const result = this.fns.GetL1SignatureBody(txType, txInfo);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  parseAuthToken(token: string): string {
+    const result = this.fns.ParseAuthToken(token);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  /** VerifyAuthToken returns NULL when valid, or the reason it's invalid */
+  verifyAuthToken(token: string, pubKey: string): void {
+    this.check(this.fns.VerifyAuthToken(token, pubKey));
+  }
+
+  /** SignTx signs any tx type given its request as JSON, with the fields of the matching types.*TxReq & the optional Nonce & ExpiredAt */
+  signTx(handle: number, txType: number, jsonRequest: string): LighterResultValue {
+    const result = this.fns.SignTx(handle, txType, jsonRequest);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, result.errCode);
+      }
+      return { version: result.version, txInfo: this.str(result.txInfo), txHash: this.str(result.txHash), messageToSign: this.str(result.messageToSign) };
+    } finally {
+      this.fns.FreeLighterResult(result);
+    }
+  }
+
+  signChangePubKeyWithHandle(handle: number, pubKey: string, nonce: number): string {
+    const result = this.fns.SignChangePubKeyWithHandle(handle, pubKey, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signCreateOrderWithHandle(handle: number, marketIndex: number, clientOrderIndex: number, baseAmount: number, price: number, isAsk: number, orderType: number, timeInForce: number, reduceOnly: number, triggerPrice: number, orderExpiry: number, nonce: number): string {
+    const result = this.fns.SignCreateOrderWithHandle(handle, marketIndex, clientOrderIndex, baseAmount, price, isAsk, orderType, timeInForce, reduceOnly, triggerPrice, orderExpiry, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signCancelOrderWithHandle(handle: number, marketIndex: number, orderIndex: number, nonce: number): string {
+    const result = this.fns.SignCancelOrderWithHandle(handle, marketIndex, orderIndex, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signWithdrawWithHandle(handle: number, usdcAmount: number, nonce: number): string {
+    const result = this.fns.SignWithdrawWithHandle(handle, usdcAmount, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signCreateSubAccountWithHandle(handle: number, nonce: number): string {
+    const result = this.fns.SignCreateSubAccountWithHandle(handle, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signCancelAllOrdersWithHandle(handle: number, timeInForce: number, time: number, nonce: number): string {
+    const result = this.fns.SignCancelAllOrdersWithHandle(handle, timeInForce, time, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signModifyOrderWithHandle(handle: number, marketIndex: number, index: number, baseAmount: number, price: number, triggerPrice: number, nonce: number): string {
+    const result = this.fns.SignModifyOrderWithHandle(handle, marketIndex, index, baseAmount, price, triggerPrice, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signTransferWithHandle(handle: number, toAccountIndex: number, usdcAmount: number, fee: number, memo: string, nonce: number): string {
+    const result = this.fns.SignTransferWithHandle(handle, toAccountIndex, usdcAmount, fee, memo, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signCreatePublicPoolWithHandle(handle: number, operatorFee: number, initialTotalShares: number, minOperatorShareRate: number, nonce: number): string {
+    const result = this.fns.SignCreatePublicPoolWithHandle(handle, operatorFee, initialTotalShares, minOperatorShareRate, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signUpdatePublicPoolWithHandle(handle: number, publicPoolIndex: number, status: number, operatorFee: number, minOperatorShareRate: number, nonce: number): string {
+    const result = this.fns.SignUpdatePublicPoolWithHandle(handle, publicPoolIndex, status, operatorFee, minOperatorShareRate, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signMintSharesWithHandle(handle: number, publicPoolIndex: number, shareAmount: number, nonce: number): string {
+    const result = this.fns.SignMintSharesWithHandle(handle, publicPoolIndex, shareAmount, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signBurnSharesWithHandle(handle: number, publicPoolIndex: number, shareAmount: number, nonce: number): string {
+    const result = this.fns.SignBurnSharesWithHandle(handle, publicPoolIndex, shareAmount, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signUpdateLeverageWithHandle(handle: number, marketIndex: number, initialMarginFraction: number, marginMode: number, nonce: number): string {
+    const result = this.fns.SignUpdateLeverageWithHandle(handle, marketIndex, initialMarginFraction, marginMode, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signUpdateMarginWithHandle(handle: number, marketIndex: number, usdcAmount: number, direction: number, nonce: number): string {
+    const result = this.fns.SignUpdateMarginWithHandle(handle, marketIndex, usdcAmount, direction, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  createAuthTokenWithHandle(handle: number, deadline: number): string {
+    const result = this.fns.CreateAuthTokenWithHandle(handle, deadline);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signChangePubKey(pubKey: string, nonce: number): string {
+    const result = this.fns.SignChangePubKey(pubKey, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signCreateOrder(marketIndex: number, clientOrderIndex: number, baseAmount: number, price: number, isAsk: number, orderType: number, timeInForce: number, reduceOnly: number, triggerPrice: number, orderExpiry: number, nonce: number): string {
+    const result = this.fns.SignCreateOrder(marketIndex, clientOrderIndex, baseAmount, price, isAsk, orderType, timeInForce, reduceOnly, triggerPrice, orderExpiry, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signCancelOrder(marketIndex: number, orderIndex: number, nonce: number): string {
+    const result = this.fns.SignCancelOrder(marketIndex, orderIndex, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signWithdraw(usdcAmount: number, nonce: number): string {
+    const result = this.fns.SignWithdraw(usdcAmount, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signCreateSubAccount(nonce: number): string {
+    const result = this.fns.SignCreateSubAccount(nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signCancelAllOrders(timeInForce: number, time: number, nonce: number): string {
+    const result = this.fns.SignCancelAllOrders(timeInForce, time, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signModifyOrder(marketIndex: number, index: number, baseAmount: number, price: number, triggerPrice: number, nonce: number): string {
+    const result = this.fns.SignModifyOrder(marketIndex, index, baseAmount, price, triggerPrice, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signTransfer(toAccountIndex: number, usdcAmount: number, fee: number, memo: string, nonce: number): string {
+    const result = this.fns.SignTransfer(toAccountIndex, usdcAmount, fee, memo, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signCreatePublicPool(operatorFee: number, initialTotalShares: number, minOperatorShareRate: number, nonce: number): string {
+    const result = this.fns.SignCreatePublicPool(operatorFee, initialTotalShares, minOperatorShareRate, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signUpdatePublicPool(publicPoolIndex: number, status: number, operatorFee: number, minOperatorShareRate: number, nonce: number): string {
+    const result = this.fns.SignUpdatePublicPool(publicPoolIndex, status, operatorFee, minOperatorShareRate, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signMintShares(publicPoolIndex: number, shareAmount: number, nonce: number): string {
+    const result = this.fns.SignMintShares(publicPoolIndex, shareAmount, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signBurnShares(publicPoolIndex: number, shareAmount: number, nonce: number): string {
+    const result = this.fns.SignBurnShares(publicPoolIndex, shareAmount, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signUpdateLeverage(marketIndex: number, initialMarginFraction: number, marginMode: number, nonce: number): string {
+    const result = this.fns.SignUpdateLeverage(marketIndex, initialMarginFraction, marginMode, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  signUpdateMargin(marketIndex: number, usdcAmount: number, direction: number, nonce: number): string {
+    const result = this.fns.SignUpdateMargin(marketIndex, usdcAmount, direction, nonce);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+
+  createAuthToken(deadline: number): string {
+    const result = this.fns.CreateAuthToken(deadline);
+    try {
+      if (result.err !== null) {
+        throw new LighterError(this.str(result.err) as string, 0);
+      }
+      return this.str(result.str) as string;
+    } finally {
+      this.fns.FreeStrOrErr(result);
+    }
+  }
+}
@@ -0,0 +1,63 @@
+// Loads the shared library built by `just build-linux-local` and signs a sample order through the generated bindings.
+//
+// The library path defaults to build/signer-amd64.so and can be overridden with LIGHTER_SIGNER_LIB.
+
+import assert from "node:assert/strict";
+import { after, before, test } from "node:test";
+import { fileURLToPath } from "node:url";
+
+import { LIGHTER_ERR_INVALID_HANDLE, LighterError, LighterSigner } from "./lighter_signer.js";
+
+// compiled to bindings/node/dist
+const LIB_PATH = process.env.LIGHTER_SIGNER_LIB ?? fileURLToPath(new URL("../../../build/signer-amd64.so", import.meta.url));
+
+const CHAIN_ID = 304;
+const API_KEY_INDEX = 3;
+const ACCOUNT_INDEX = 7;
+const TX_TYPE_CREATE_ORDER = 14;
+
+let signer: LighterSigner;
+let publicKey: string;
+let handle: number;
+
+before(() => {
+  signer = new LighterSigner(LIB_PATH);
+  const key = signer.generateAPIKey("");
+  publicKey = key.publicKey as string;
+  // without URL, the client doesn't make any HTTP call so nonces are given
+  handle = signer.createClientHandle("", key.privateKey as string, CHAIN_ID, API_KEY_INDEX, ACCOUNT_INDEX);
+});
+
+after(() => {
+  signer.destroyClientHandle(handle);
+});
+
+test("sign order", () => {
+  const txInfo = signer.signCreateOrderWithHandle(handle, 0, 1, 1000, 1000, 0, 0, 1, 0, 0, -1, 1);
+  const tx = JSON.parse(txInfo);
+  assert.equal(tx.AccountIndex, ACCOUNT_INDEX);
+  assert.equal(tx.ApiKeyIndex, API_KEY_INDEX);
+  assert.equal(tx.BaseAmount, 1000);
+  assert.equal(tx.Price, 1000);
+  assert.equal(tx.Nonce, 1);
+  assert.ok(tx.Sig);
+
+  signer.verifyTxSignature(TX_TYPE_CREATE_ORDER, txInfo, publicKey, CHAIN_ID);
+  assert.equal(signer.outstandingAllocations(), 0);
+});
+
+test("sign tx", () => {
+  const request = JSON.stringify({ MarketIndex: 0, ClientOrderIndex: 2, BaseAmount: 1000, Price: 1000, Type: 1, TimeInForce: 0, Nonce: 2 });
+  const result = signer.signTx(handle, TX_TYPE_CREATE_ORDER, request);
+  assert.equal(result.txHash, signer.computeTxHash(TX_TYPE_CREATE_ORDER, result.txInfo as string, CHAIN_ID));
+  assert.equal(signer.outstandingAllocations(), 0);
+});
+
+test("errors", () => {
+  assert.throws(() => signer.signCreateOrderWithHandle(handle, 0, 1, 0, 1000, 0, 0, 1, 0, 0, -1, 1), LighterError);
+  assert.throws(
+    () => signer.signTx(handle + 1000, TX_TYPE_CREATE_ORDER, "{}"),
+    (err: unknown) => err instanceof LighterError && err.code === LIGHTER_ERR_INVALID_HANDLE,
+  );
+  assert.equal(signer.outstandingAllocations(), 0);
+});
@@ -0,0 +1,531 @@
+# Code generated by sharedlib/bindgen from sharedlib/exports.json. DO NOT EDIT.
+"""Typed ctypes bindings of the lighter-go signer shared library.
+
+Strings returned by the library are copied & freed by the wrappers, and errors are raised as LighterError.
+"""
+
+import ctypes
+from typing import Optional
+
+LIGHTER_RESULT_VERSION = 1
+LIGHTER_OK = 0
+LIGHTER_ERR_INVALID_HANDLE = 1
+LIGHTER_ERR_INVALID_REQUEST = 2
+LIGHTER_ERR_TX = 3
+LIGHTER_ERR_PANIC = 4
+
+
+class LighterError(Exception):
+    def __init__(self, message: str, code: int = 0):
+        super().__init__(message)
+        self.code = code
+
+
+class StrOrErr(ctypes.Structure):
+    _fields_ = [
+        ("str", ctypes.c_void_p),
+        ("err", ctypes.c_void_p),
+    ]
+
+
+class ApiKeyResponse(ctypes.Structure):
+    _fields_ = [
+        ("privateKey", ctypes.c_void_p),
+        ("publicKey", ctypes.c_void_p),
+        ("err", ctypes.c_void_p),
+    ]
+
+
+class HandleOrErr(ctypes.Structure):
+    _fields_ = [
+        ("handle", ctypes.c_longlong),
+        ("err", ctypes.c_void_p),
+    ]
+
+
+class LighterResult(ctypes.Structure):
+    _fields_ = [
+        ("version", ctypes.c_int),
+        ("errCode", ctypes.c_int),
+        ("txInfo", ctypes.c_void_p),
+        ("txHash", ctypes.c_void_p),
+        ("messageToSign", ctypes.c_void_p),
+        ("err", ctypes.c_void_p),
+    ]
+
+
+class Signer:
+    def __init__(self, path: str):
+        self._lib = ctypes.CDLL(path)
+        self._lib.FreeString.argtypes = [ctypes.c_void_p]
+        self._lib.FreeString.restype = None
+        self._lib.FreeStrOrErr.argtypes = [StrOrErr]
+        self._lib.FreeStrOrErr.restype = None
+        self._lib.FreeApiKeyResponse.argtypes = [ApiKeyResponse]
+        self._lib.FreeApiKeyResponse.restype = None
+        self._lib.FreeHandleOrErr.argtypes = [HandleOrErr]
+        self._lib.FreeHandleOrErr.restype = None
+        self._lib.FreeLighterResult.argtypes = [LighterResult]
+        self._lib.FreeLighterResult.restype = None
+        self._lib.OutstandingAllocations.argtypes = []
+        self._lib.OutstandingAllocations.restype = ctypes.c_longlong
+        self._lib.GenerateAPIKey.argtypes = [ctypes.c_char_p]
+        self._lib.GenerateAPIKey.restype = ApiKeyResponse
+        self._lib.CreateClientHandle.argtypes = [ctypes.c_char_p, ctypes.c_char_p, ctypes.c_int, ctypes.c_int, ctypes.c_longlong]
+        self._lib.CreateClientHandle.restype = HandleOrErr
+        self._lib.DestroyClientHandle.argtypes = [ctypes.c_longlong]
+        self._lib.DestroyClientHandle.restype = ctypes.c_void_p
+        self._lib.CheckClientHandle.argtypes = [ctypes.c_longlong]
+        self._lib.CheckClientHandle.restype = ctypes.c_void_p
+        self._lib.CreateClient.argtypes = [ctypes.c_char_p, ctypes.c_char_p, ctypes.c_int, ctypes.c_int, ctypes.c_longlong]
+        self._lib.CreateClient.restype = ctypes.c_void_p
+        self._lib.CheckClient.argtypes = [ctypes.c_int, ctypes.c_longlong]
+        self._lib.CheckClient.restype = ctypes.c_void_p
+        self._lib.SwitchAPIKey.argtypes = [ctypes.c_int]
+        self._lib.SwitchAPIKey.restype = ctypes.c_void_p
+        self._lib.DerivePublicKey.argtypes = [ctypes.c_char_p]
+        self._lib.DerivePublicKey.restype = StrOrErr
+        self._lib.ComputeTxHash.argtypes = [ctypes.c_int, ctypes.c_char_p, ctypes.c_int]
+        self._lib.ComputeTxHash.restype = StrOrErr
+        self._lib.VerifyTxSignature.argtypes = [ctypes.c_int, ctypes.c_char_p, ctypes.c_char_p, ctypes.c_int]
+        self._lib.VerifyTxSignature.restype = ctypes.c_void_p
+        self._lib.GetL1SignatureBody.argtypes = [ctypes.c_int, ctypes.c_char_p]
+        self._lib.GetL1SignatureBody.restype = StrOrErr
+        self._lib.ParseAuthToken.argtypes = [ctypes.c_char_p]
+        self._lib.ParseAuthToken.restype = StrOrErr
+        self._lib.VerifyAuthToken.argtypes = [ctypes.c_char_p, ctypes.c_char_p]
+        self._lib.VerifyAuthToken.restype = ctypes.c_void_p
+        self._lib.SignTx.argtypes = [ctypes.c_longlong, ctypes.c_int, ctypes.c_char_p]
+        self._lib.SignTx.restype = LighterResult
+        self._lib.SignChangePubKeyWithHandle.argtypes = [ctypes.c_longlong, ctypes.c_char_p, ctypes.c_longlong]
+        self._lib.SignChangePubKeyWithHandle.restype = StrOrErr
+        self._lib.SignCreateOrderWithHandle.argtypes = [ctypes.c_longlong, ctypes.c_int, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_int, ctypes.c_int, ctypes.c_int, ctypes.c_int, ctypes.c_int, ctypes.c_int, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignCreateOrderWithHandle.restype = StrOrErr
+        self._lib.SignCancelOrderWithHandle.argtypes = [ctypes.c_longlong, ctypes.c_int, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignCancelOrderWithHandle.restype = StrOrErr
+        self._lib.SignWithdrawWithHandle.argtypes = [ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignWithdrawWithHandle.restype = StrOrErr
+        self._lib.SignCreateSubAccountWithHandle.argtypes = [ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignCreateSubAccountWithHandle.restype = StrOrErr
+        self._lib.SignCancelAllOrdersWithHandle.argtypes = [ctypes.c_longlong, ctypes.c_int, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignCancelAllOrdersWithHandle.restype = StrOrErr
+        self._lib.SignModifyOrderWithHandle.argtypes = [ctypes.c_longlong, ctypes.c_int, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignModifyOrderWithHandle.restype = StrOrErr
+        self._lib.SignTransferWithHandle.argtypes = [ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_char_p, ctypes.c_longlong]
+        self._lib.SignTransferWithHandle.restype = StrOrErr
+        self._lib.SignCreatePublicPoolWithHandle.argtypes = [ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignCreatePublicPoolWithHandle.restype = StrOrErr
+        self._lib.SignUpdatePublicPoolWithHandle.argtypes = [ctypes.c_longlong, ctypes.c_longlong, ctypes.c_int, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignUpdatePublicPoolWithHandle.restype = StrOrErr
+        self._lib.SignMintSharesWithHandle.argtypes = [ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignMintSharesWithHandle.restype = StrOrErr
+        self._lib.SignBurnSharesWithHandle.argtypes = [ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignBurnSharesWithHandle.restype = StrOrErr
+        self._lib.SignUpdateLeverageWithHandle.argtypes = [ctypes.c_longlong, ctypes.c_int, ctypes.c_int, ctypes.c_int, ctypes.c_longlong]
+        self._lib.SignUpdateLeverageWithHandle.restype = StrOrErr
+        self._lib.SignUpdateMarginWithHandle.argtypes = [ctypes.c_longlong, ctypes.c_int, ctypes.c_longlong, ctypes.c_int, ctypes.c_longlong]
+        self._lib.SignUpdateMarginWithHandle.restype = StrOrErr
+        self._lib.CreateAuthTokenWithHandle.argtypes = [ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.CreateAuthTokenWithHandle.restype = StrOrErr
+        self._lib.SignChangePubKey.argtypes = [ctypes.c_char_p, ctypes.c_longlong]
+        self._lib.SignChangePubKey.restype = StrOrErr
+        self._lib.SignCreateOrder.argtypes = [ctypes.c_int, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_int, ctypes.c_int, ctypes.c_int, ctypes.c_int, ctypes.c_int, ctypes.c_int, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignCreateOrder.restype = StrOrErr
+        self._lib.SignCancelOrder.argtypes = [ctypes.c_int, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignCancelOrder.restype = StrOrErr
+        self._lib.SignWithdraw.argtypes = [ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignWithdraw.restype = StrOrErr
+        self._lib.SignCreateSubAccount.argtypes = [ctypes.c_longlong]
+        self._lib.SignCreateSubAccount.restype = StrOrErr
+        self._lib.SignCancelAllOrders.argtypes = [ctypes.c_int, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignCancelAllOrders.restype = StrOrErr
+        self._lib.SignModifyOrder.argtypes = [ctypes.c_int, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignModifyOrder.restype = StrOrErr
+        self._lib.SignTransfer.argtypes = [ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_char_p, ctypes.c_longlong]
+        self._lib.SignTransfer.restype = StrOrErr
+        self._lib.SignCreatePublicPool.argtypes = [ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignCreatePublicPool.restype = StrOrErr
+        self._lib.SignUpdatePublicPool.argtypes = [ctypes.c_longlong, ctypes.c_int, ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignUpdatePublicPool.restype = StrOrErr
+        self._lib.SignMintShares.argtypes = [ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignMintShares.restype = StrOrErr
+        self._lib.SignBurnShares.argtypes = [ctypes.c_longlong, ctypes.c_longlong, ctypes.c_longlong]
+        self._lib.SignBurnShares.restype = StrOrErr
+        self._lib.SignUpdateLeverage.argtypes = [ctypes.c_int, ctypes.c_int, ctypes.c_int, ctypes.c_longlong]
+        self._lib.SignUpdateLeverage.restype = StrOrErr
+        self._lib.SignUpdateMargin.argtypes = [ctypes.c_int, ctypes.c_longlong, ctypes.c_int, ctypes.c_longlong]
+        self._lib.SignUpdateMargin.restype = StrOrErr
+        self._lib.CreateAuthToken.argtypes = [ctypes.c_longlong]
+        self._lib.CreateAuthToken.restype = StrOrErr
+
+    def _string(self, ptr: Optional[int]) -> Optional[str]:
+        if not ptr:
+            return None
+        return ctypes.string_at(ptr).decode()
+
+    def _check(self, ptr: Optional[int]) -> None:
+        if ptr:
+            message = self._string(ptr)
+            self._lib.FreeString(ptr)
+            raise LighterError(message)
+
+    def outstanding_allocations(self) -> int:
+        "OutstandingAllocations returns how many strings returned by the library were not freed yet"
+        return self._lib.OutstandingAllocations()
+
+    def generate_api_key(self, seed: str) -> dict:
+        result = self._lib.GenerateAPIKey(seed.encode())
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return {"privateKey": self._string(result.privateKey), "publicKey": self._string(result.publicKey)}
+        finally:
+            self._lib.FreeApiKeyResponse(result)
+
+    def create_client_handle(self, url: str, private_key: str, chain_id: int, api_key_index: int, account_index: int) -> int:
+        result = self._lib.CreateClientHandle(url.encode(), private_key.encode(), chain_id, api_key_index, account_index)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return result.handle
+        finally:
+            self._lib.FreeHandleOrErr(result)
+
+    def destroy_client_handle(self, handle: int) -> None:
+        self._check(self._lib.DestroyClientHandle(handle))
+
+    def check_client_handle(self, handle: int) -> None:
+        self._check(self._lib.CheckClientHandle(handle))
+
+    def create_client(self, url: str, private_key: str, chain_id: int, api_key_index: int, account_index: int) -> None:
+        self._check(self._lib.CreateClient(url.encode(), private_key.encode(), chain_id, api_key_index, account_index))
+
+    def check_client(self, api_key_index: int, account_index: int) -> None:
+        self._check(self._lib.CheckClient(api_key_index, account_index))
+
+    def switch_api_key(self, api_key_index: int) -> None:
+        self._check(self._lib.SwitchAPIKey(api_key_index))
+
+    def derive_public_key(self, private_key: str) -> str:
+        result = self._lib.DerivePublicKey(private_key.encode())
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def compute_tx_hash(self, tx_type: int, tx_info: str, chain_id: int) -> str:
+        result = self._lib.ComputeTxHash(tx_type, tx_info.encode(), chain_id)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def verify_tx_signature(self, tx_type: int, tx_info: str, pub_key: str, chain_id: int) -> None:
+        "VerifyTxSignature returns NULL when valid, or the reason it's invalid"
+        self._check(self._lib.VerifyTxSignature(tx_type, tx_info.encode(), pub_key.encode(), chain_id))
+
+    def get_l1signature_body(self, tx_type: int, tx_info: str) -> str:
+        result = self._lib.GetL1SignatureBody(tx_type, tx_info.encode())
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def parse_auth_token(self, token: str) -> str:
+        result = self._lib.ParseAuthToken(token.encode())
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def verify_auth_token(self, token: str, pub_key: str) -> None:
+        "VerifyAuthToken returns NULL when valid, or the reason it's invalid"
+        self._check(self._lib.VerifyAuthToken(token.encode(), pub_key.encode()))
+
+    def sign_tx(self, handle: int, tx_type: int, json_request: str) -> dict:
+        "SignTx signs any tx type given its request as JSON, with the fields of the matching types.*TxReq & the optional Nonce & ExpiredAt"
+        result = self._lib.SignTx(handle, tx_type, json_request.encode())
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), result.errCode)
+            return {"version": result.version, "txInfo": self._string(result.txInfo), "txHash": self._string(result.txHash), "messageToSign": self._string(result.messageToSign)}
+        finally:
+            self._lib.FreeLighterResult(result)
+
+    def sign_change_pub_key_with_handle(self, handle: int, pub_key: str, nonce: int) -> str:
+        result = self._lib.SignChangePubKeyWithHandle(handle, pub_key.encode(), nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_create_order_with_handle(self, handle: int, market_index: int, client_order_index: int, base_amount: int, price: int, is_ask: int, order_type: int, time_in_force: int, reduce_only: int, trigger_price: int, order_expiry: int, nonce: int) -> str:
+        result = self._lib.SignCreateOrderWithHandle(handle, market_index, client_order_index, base_amount, price, is_ask, order_type, time_in_force, reduce_only, trigger_price, order_expiry, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_cancel_order_with_handle(self, handle: int, market_index: int, order_index: int, nonce: int) -> str:
+        result = self._lib.SignCancelOrderWithHandle(handle, market_index, order_index, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_withdraw_with_handle(self, handle: int, usdc_amount: int, nonce: int) -> str:
+        result = self._lib.SignWithdrawWithHandle(handle, usdc_amount, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_create_sub_account_with_handle(self, handle: int, nonce: int) -> str:
+        result = self._lib.SignCreateSubAccountWithHandle(handle, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_cancel_all_orders_with_handle(self, handle: int, time_in_force: int, time: int, nonce: int) -> str:
+        result = self._lib.SignCancelAllOrdersWithHandle(handle, time_in_force, time, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_modify_order_with_handle(self, handle: int, market_index: int, index: int, base_amount: int, price: int, trigger_price: int, nonce: int) -> str:
+        result = self._lib.SignModifyOrderWithHandle(handle, market_index, index, base_amount, price, trigger_price, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_transfer_with_handle(self, handle: int, to_account_index: int, usdc_amount: int, fee: int, memo: str, nonce: int) -> str:
+        result = self._lib.SignTransferWithHandle(handle, to_account_index, usdc_amount, fee, memo.encode(), nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_create_public_pool_with_handle(self, handle: int, operator_fee: int, initial_total_shares: int, min_operator_share_rate: int, nonce: int) -> str:
+        result = self._lib.SignCreatePublicPoolWithHandle(handle, operator_fee, initial_total_shares, min_operator_share_rate, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_update_public_pool_with_handle(self, handle: int, public_pool_index: int, status: int, operator_fee: int, min_operator_share_rate: int, nonce: int) -> str:
+        result = self._lib.SignUpdatePublicPoolWithHandle(handle, public_pool_index, status, operator_fee, min_operator_share_rate, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_mint_shares_with_handle(self, handle: int, public_pool_index: int, share_amount: int, nonce: int) -> str:
+        result = self._lib.SignMintSharesWithHandle(handle, public_pool_index, share_amount, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_burn_shares_with_handle(self, handle: int, public_pool_index: int, share_amount: int, nonce: int) -> str:
+        result = self._lib.SignBurnSharesWithHandle(handle, public_pool_index, share_amount, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_update_leverage_with_handle(self, handle: int, market_index: int, initial_margin_fraction: int, margin_mode: int, nonce: int) -> str:
+        result = self._lib.SignUpdateLeverageWithHandle(handle, market_index, initial_margin_fraction, margin_mode, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_update_margin_with_handle(self, handle: int, market_index: int, usdc_amount: int, direction: int, nonce: int) -> str:
+        result = self._lib.SignUpdateMarginWithHandle(handle, market_index, usdc_amount, direction, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def create_auth_token_with_handle(self, handle: int, deadline: int) -> str:
+        result = self._lib.CreateAuthTokenWithHandle(handle, deadline)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_change_pub_key(self, pub_key: str, nonce: int) -> str:
+        result = self._lib.SignChangePubKey(pub_key.encode(), nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_create_order(self, market_index: int, client_order_index: int, base_amount: int, price: int, is_ask: int, order_type: int, time_in_force: int, reduce_only: int, trigger_price: int, order_expiry: int, nonce: int) -> str:
+        result = self._lib.SignCreateOrder(market_index, client_order_index, base_amount, price, is_ask, order_type, time_in_force, reduce_only, trigger_price, order_expiry, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_cancel_order(self, market_index: int, order_index: int, nonce: int) -> str:
+        result = self._lib.SignCancelOrder(market_index, order_index, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_withdraw(self, usdc_amount: int, nonce: int) -> str:
+        result = self._lib.SignWithdraw(usdc_amount, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_create_sub_account(self, nonce: int) -> str:
+        result = self._lib.SignCreateSubAccount(nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_cancel_all_orders(self, time_in_force: int, time: int, nonce: int) -> str:
+        result = self._lib.SignCancelAllOrders(time_in_force, time, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_modify_order(self, market_index: int, index: int, base_amount: int, price: int, trigger_price: int, nonce: int) -> str:
+        result = self._lib.SignModifyOrder(market_index, index, base_amount, price, trigger_price, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_transfer(self, to_account_index: int, usdc_amount: int, fee: int, memo: str, nonce: int) -> str:
+        result = self._lib.SignTransfer(to_account_index, usdc_amount, fee, memo.encode(), nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_create_public_pool(self, operator_fee: int, initial_total_shares: int, min_operator_share_rate: int, nonce: int) -> str:
+        result = self._lib.SignCreatePublicPool(operator_fee, initial_total_shares, min_operator_share_rate, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_update_public_pool(self, public_pool_index: int, status: int, operator_fee: int, min_operator_share_rate: int, nonce: int) -> str:
+        result = self._lib.SignUpdatePublicPool(public_pool_index, status, operator_fee, min_operator_share_rate, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_mint_shares(self, public_pool_index: int, share_amount: int, nonce: int) -> str:
+        result = self._lib.SignMintShares(public_pool_index, share_amount, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_burn_shares(self, public_pool_index: int, share_amount: int, nonce: int) -> str:
+        result = self._lib.SignBurnShares(public_pool_index, share_amount, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_update_leverage(self, market_index: int, initial_margin_fraction: int, margin_mode: int, nonce: int) -> str:
+        result = self._lib.SignUpdateLeverage(market_index, initial_margin_fraction, margin_mode, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def sign_update_margin(self, market_index: int, usdc_amount: int, direction: int, nonce: int) -> str:
+        result = self._lib.SignUpdateMargin(market_index, usdc_amount, direction, nonce)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
+
+    def create_auth_token(self, deadline: int) -> str:
+        result = self._lib.CreateAuthToken(deadline)
+        try:
+            if result.err:
+                raise LighterError(self._string(result.err), 0)
+            return self._string(result.str)
+        finally:
+            self._lib.FreeStrOrErr(result)
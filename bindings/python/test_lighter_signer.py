@@ -0,0 +1,65 @@
+"""Loads the shared library built by `just build-linux-local` and signs a sample order through the generated bindings.
+
+The library path defaults to build/signer-amd64.so and can be overridden with LIGHTER_SIGNER_LIB.
+"""
+
+import json
+import os
+import unittest
+
+from lighter_signer import LIGHTER_ERR_INVALID_HANDLE, LighterError, Signer
+
+ROOT = os.path.dirname(os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+LIB_PATH = os.environ.get("LIGHTER_SIGNER_LIB", os.path.join(ROOT, "build", "signer-amd64.so"))
+
+CHAIN_ID = 304
+API_KEY_INDEX = 3
+ACCOUNT_INDEX = 7
+TX_TYPE_CREATE_ORDER = 14
+
+
+class SignerTest(unittest.TestCase):
+    @classmethod
+    def setUpClass(cls):
+        cls.signer = Signer(LIB_PATH)
+        key = cls.signer.generate_api_key("")
+        cls.public_key = key["publicKey"]
+        # without URL, the client doesn't make any HTTP call so nonces are given
+        cls.handle = cls.signer.create_client_handle("", key["privateKey"], CHAIN_ID, API_KEY_INDEX, ACCOUNT_INDEX)
+
+    @classmethod
+    def tearDownClass(cls):
+        cls.signer.destroy_client_handle(cls.handle)
+
+    def test_sign_order(self):
+        tx_info = self.signer.sign_create_order_with_handle(
+            self.handle, 0, 1, 1000, 1000, 0, 0, 1, 0, 0, -1, 1
+        )
+        tx = json.loads(tx_info)
+        self.assertEqual(tx["AccountIndex"], ACCOUNT_INDEX)
+        self.assertEqual(tx["ApiKeyIndex"], API_KEY_INDEX)
+        self.assertEqual(tx["BaseAmount"], 1000)
+        self.assertEqual(tx["Price"], 1000)
+        self.assertEqual(tx["Nonce"], 1)
+        self.assertTrue(tx["Sig"])
+
+        self.signer.verify_tx_signature(TX_TYPE_CREATE_ORDER, tx_info, self.public_key, CHAIN_ID)
+        self.assertEqual(self.signer.outstanding_allocations(), 0)
+
+    def test_sign_tx(self):
+        request = json.dumps({"MarketIndex": 0, "ClientOrderIndex": 2, "BaseAmount": 1000, "Price": 1000, "Type": 1, "TimeInForce": 0, "Nonce": 2})
+        result = self.signer.sign_tx(self.handle, TX_TYPE_CREATE_ORDER, request)
+        self.assertEqual(result["txHash"], self.signer.compute_tx_hash(TX_TYPE_CREATE_ORDER, result["txInfo"], CHAIN_ID))
+        self.assertEqual(self.signer.outstanding_allocations(), 0)
+
+    def test_errors(self):
+        with self.assertRaises(LighterError):
+            self.signer.sign_create_order_with_handle(self.handle, 0, 1, 0, 1000, 0, 0, 1, 0, 0, -1, 1)
+        with self.assertRaises(LighterError) as ctx:
+            self.signer.sign_tx(self.handle + 1000, TX_TYPE_CREATE_ORDER, "{}")
+        self.assertEqual(ctx.exception.code, LIGHTER_ERR_INVALID_HANDLE)
+        self.assertEqual(self.signer.outstanding_allocations(), 0)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+const (
+	defaultCloseSlippage    = 0.01
+	defaultCloseMaxAttempts = 3
+)
+
+type CloseOptions struct {
+	// MaxSlippage is the max relative distance from the best price on the other side of the book. Defaults to 1%.
+	MaxSlippage float64
+	// LimitIOC closes using limit IOC orders instead of market orders
+	LimitIOC bool
+	// MaxAttempts is how many orders are sent at most, to close what's left after partial fills. Defaults to 3.
+	MaxAttempts int
+}
+
+type CloseResult struct {
+	MarketIndex uint8
+	// PositionBaseAmount is the absolute position size before closing
+	PositionBaseAmount int64
+	// TargetBaseAmount is how much of the position was meant to be closed
+	TargetBaseAmount int64
+	ClosedBaseAmount int64
+	TxHashes         []string
+}
+
+// ClosePosition closes fraction (between 0 and 1) of the position in the market with reduce only orders,
+// bounded by MaxSlippage from the order book. Partial fills are retried until MaxAttempts orders were sent.
+func (c *TxClient) ClosePosition(ctx context.Context, marketIndex uint8, fraction float64, opts *CloseOptions) (*CloseResult, error) {
+	if fraction <= 0 || fraction > 1 {
+		return nil, fmt.Errorf("fraction should be larger than 0 and not larger than 1")
+	}
+	if c.apiClient == nil {
+		return nil, fmt.Errorf("HTTPClient is nil, can't get positions")
+	}
+	opts = fillCloseOptions(opts)
+
+	market, err := c.market(marketIndex)
+	if err != nil {
+		return nil, err
+	}
+	size, sign, err := c.positionSize(market)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CloseResult{
+		MarketIndex:        marketIndex,
+		PositionBaseAmount: size,
+		TargetBaseAmount:   int64(math.Floor(float64(size) * fraction)),
+	}
+	keep := size - result.TargetBaseAmount
+
+	current := size
+	for attempt := 0; attempt < opts.MaxAttempts && current > keep; attempt++ {
+		order, err := c.closeOrder(market, current-keep, sign, opts)
+		if err != nil {
+			return result, err
+		}
+		txInfo, err := c.GetCreateOrderTransaction(order, nil)
+		if err != nil {
+			return result, err
+		}
+		status, err := c.SendAndWait(ctx, txInfo)
+		if err != nil {
+			return result, err
+		}
+		result.TxHashes = append(result.TxHashes, status.TxHash)
+		if status.State == TxStateDryRun {
+			break
+		}
+
+		current, _, err = c.positionSize(market)
+		if err != nil {
+			return result, err
+		}
+		result.ClosedBaseAmount = size - current
+	}
+
+	if result.ClosedBaseAmount < result.TargetBaseAmount && len(result.TxHashes) == opts.MaxAttempts {
+		return result, fmt.Errorf("closed %v out of %v after %v orders", result.ClosedBaseAmount, result.TargetBaseAmount, opts.MaxAttempts)
+	}
+	return result, nil
+}
+
+// CloseAll fully closes every open position. Markets which fail to close don't stop the others from being closed.
+func (c *TxClient) CloseAll(ctx context.Context, opts *CloseOptions) ([]*CloseResult, error) {
+	if c.apiClient == nil {
+		return nil, fmt.Errorf("HTTPClient is nil, can't get positions")
+	}
+	account, err := c.apiClient.GetAccount(c.accountIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*CloseResult, 0)
+	var errs []error
+	for _, position := range account.Positions {
+		size, err := parseDecimal(position.Position)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if size == 0 {
+			continue
+		}
+
+		result, err := c.ClosePosition(ctx, position.MarketIndex, 1, opts)
+		if result != nil {
+			results = append(results, result)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to close position in market %v. err: %w", position.MarketIndex, err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+func fillCloseOptions(opts *CloseOptions) *CloseOptions {
+	ret := CloseOptions{}
+	if opts != nil {
+		ret = *opts
+	}
+	if ret.MaxSlippage <= 0 {
+		ret.MaxSlippage = defaultCloseSlippage
+	}
+	if ret.MaxAttempts <= 0 {
+		ret.MaxAttempts = defaultCloseMaxAttempts
+	}
+	return &ret
+}
+
+// positionSize returns the absolute position size in the market, along with its sign (1 for long, -1 for short)
+func (c *TxClient) positionSize(market *OrderBookDetail) (int64, int32, error) {
+	account, err := c.apiClient.GetAccount(c.accountIndex)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, position := range account.Positions {
+		if position.MarketIndex != market.MarketIndex {
+			continue
+		}
+		size, err := parseDecimal(position.Position)
+		if err != nil {
+			return 0, 0, err
+		}
+		return toTicks(math.Abs(size), market.SizeDecimals), position.Sign, nil
+	}
+	return 0, 0, nil
+}
+
+// closeOrder builds a reduce only order against the position, priced at the best price on the other side of the book moved by the max slippage
+func (c *TxClient) closeOrder(market *OrderBookDetail, baseAmount int64, sign int32, opts *CloseOptions) (*types.CreateOrderTxReq, error) {
+	book, err := c.apiClient.GetOrderBookOrders(market.MarketIndex, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	// longs are closed by selling into the bids, shorts by buying from the asks
+	isAsk := uint8(0)
+	side := book.Asks
+	slippage := 1 + opts.MaxSlippage
+	if sign > 0 {
+		isAsk = 1
+		side = book.Bids
+		slippage = 1 - opts.MaxSlippage
+	}
+	if len(side) == 0 {
+		return nil, fmt.Errorf("order book of market %v has no liquidity to close against", market.MarketIndex)
+	}
+	best, err := parseDecimal(side[0].Price)
+	if err != nil {
+		return nil, err
+	}
+	price := toTicks(best*slippage, market.PriceDecimals)
+	price = max(price, int64(txtypes.MinOrderPrice))
+	price = min(price, int64(txtypes.MaxOrderPrice))
+
+	order := &types.CreateOrderTxReq{
+		MarketIndex: market.MarketIndex,
+		BaseAmount:  baseAmount,
+		Price:       uint32(price),
+		IsAsk:       isAsk,
+		Type:        txtypes.MarketOrder,
+		TimeInForce: txtypes.ImmediateOrCancel,
+		ReduceOnly:  1,
+		OrderExpiry: txtypes.NilOrderExpiry,
+	}
+	if opts.LimitIOC {
+		order.Type = txtypes.LimitOrder
+	}
+	return order, nil
+}
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+// newClosePositionServer serves a position of the given size in market 1, which is closed by sent orders, and the top of its book
+func newClosePositionServer(t *testing.T, sign int32, position float64) (*HTTPClient, func() []*txtypes.L2CreateOrderTxInfo) {
+	t.Helper()
+	var mu sync.Mutex
+	var sent []*txtypes.L2CreateOrderTxInfo
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/v1/nextNonce":
+			fmt.Fprintf(w, `{"code":200,"nonce":%d}`, len(sent)+1)
+		case "/api/v1/account":
+			fmt.Fprintf(w, `{"code":200,"accounts":[{"index":7,"positions":[{"market_id":1,"sign":%d,"position":"%.2f"}]}]}`, sign, position)
+		case "/api/v1/orderBookOrders":
+			w.Write([]byte(`{"code":200,"asks":[{"price":"101.00"}],"bids":[{"price":"100.00"}]}`))
+		case "/api/v1/sendTx":
+			tx := &txtypes.L2CreateOrderTxInfo{}
+			if err := json.Unmarshal([]byte(r.FormValue("tx_info")), tx); err != nil {
+				t.Error(err)
+			}
+			sent = append(sent, tx)
+			// only half of the first order gets filled, the next ones fully
+			filled := float64(tx.BaseAmount) / 100
+			if len(sent) == 1 {
+				filled /= 2
+			}
+			position -= filled
+			fmt.Fprintf(w, `{"code":200,"tx_hash":"0x%02x"}`, len(sent))
+		case "/api/v1/tx":
+			fmt.Fprintf(w, `{"code":200,"hash":%q,"status":%v}`, r.URL.Query().Get("value"), TxStatusExecuted)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return NewHTTPClient(server.URL), func() []*txtypes.L2CreateOrderTxInfo {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*txtypes.L2CreateOrderTxInfo(nil), sent...)
+	}
+}
+
+func newClosePositionClient(t *testing.T, apiClient *HTTPClient) *TxClient {
+	t.Helper()
+	txClient := newTestTxClient(t, apiClient)
+	txClient.SetTxTracker(NewTxTracker(apiClient, time.Millisecond))
+	txClient.markets[1] = &OrderBookDetail{MarketIndex: 1, SizeDecimals: 2, PriceDecimals: 2}
+	return txClient
+}
+
+func TestClosePosition(t *testing.T) {
+	// closing half of a 2.00 long needs 2 orders, as the first one only fills half
+	apiClient, sent := newClosePositionServer(t, 1, 2)
+	txClient := newClosePositionClient(t, apiClient)
+
+	result, err := txClient.ClosePosition(context.Background(), 1, 0.5, &CloseOptions{MaxSlippage: 0.02})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PositionBaseAmount != 200 || result.TargetBaseAmount != 100 || result.ClosedBaseAmount != 100 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if len(result.TxHashes) != 2 || result.TxHashes[0] != "0x01" || result.TxHashes[1] != "0x02" {
+		t.Fatalf("unexpected tx hashes %v", result.TxHashes)
+	}
+
+	orders := sent()
+	if len(orders) != 2 {
+		t.Fatalf("expected 2 orders, got %d", len(orders))
+	}
+	// the second order only closes what the first one left
+	for i, baseAmount := range []int64{100, 50} {
+		order := orders[i]
+		if order.BaseAmount != baseAmount || order.IsAsk != 1 || order.Price != 9800 || order.ReduceOnly != 1 ||
+			order.Type != txtypes.MarketOrder || order.TimeInForce != txtypes.ImmediateOrCancel {
+			t.Fatalf("unexpected order %d %+v", i, order)
+		}
+	}
+}
+
+func TestClosePositionGivesUpAfterMaxAttempts(t *testing.T) {
+	// a short, which is closed by buying
+	apiClient, sent := newClosePositionServer(t, -1, 2)
+	txClient := newClosePositionClient(t, apiClient)
+
+	result, err := txClient.ClosePosition(context.Background(), 1, 1, &CloseOptions{MaxAttempts: 1})
+	if err == nil {
+		t.Fatal("expected an error as the position isn't closed")
+	}
+	if result.ClosedBaseAmount != 100 || result.TargetBaseAmount != 200 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	orders := sent()
+	if len(orders) != 1 || orders[0].BaseAmount != 200 || orders[0].IsAsk != 0 || orders[0].Price != 10201 || orders[0].ReduceOnly != 1 {
+		t.Fatalf("unexpected orders %+v", orders)
+	}
+}
+
+func TestCloseOrder(t *testing.T) {
+	apiClient, _ := newClosePositionServer(t, 1, 0)
+	txClient := newClosePositionClient(t, apiClient)
+	market := txClient.markets[1]
+
+	tests := []struct {
+		name  string
+		sign  int32
+		opts  *CloseOptions
+		isAsk uint8
+		price uint32
+		typ   uint8
+	}{
+		// longs sell into the bids, shorts buy from the asks
+		{name: "long", sign: 1, opts: &CloseOptions{MaxSlippage: 0.01}, isAsk: 1, price: 9900, typ: txtypes.MarketOrder},
+		{name: "short", sign: -1, opts: &CloseOptions{MaxSlippage: 0.01}, isAsk: 0, price: 10201, typ: txtypes.MarketOrder},
+		{name: "limit IOC", sign: -1, opts: &CloseOptions{MaxSlippage: 0.02, LimitIOC: true}, isAsk: 0, price: 10302, typ: txtypes.LimitOrder},
+	}
+	for _, tt := range tests {
+		order, err := txClient.closeOrder(market, 42, tt.sign, tt.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if order.MarketIndex != 1 || order.BaseAmount != 42 || order.IsAsk != tt.isAsk || order.Price != tt.price ||
+			order.Type != tt.typ || order.TimeInForce != txtypes.ImmediateOrCancel || order.ReduceOnly != 1 ||
+			order.OrderExpiry != txtypes.NilOrderExpiry {
+			t.Fatalf("%s: unexpected order %+v", tt.name, order)
+		}
+	}
+}
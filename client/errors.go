@@ -0,0 +1,22 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/uncle-gua/lighter-go/types"
+)
+
+var (
+	ErrTxHashMismatch          = errors.New("TxHash returned by Lighter does not match the signed hash")
+	ErrDryRunTx                = errors.New("tx was signed as a dry run and can't be sent")
+	ErrKillSwitchEnabled       = errors.New("kill switch is enabled, new orders & modifications are blocked")
+	ErrOrderNotionalTooHigh    = errors.New("order notional exceeds the risk limit")
+	ErrMarketNotionalTooHigh   = errors.New("market notional exceeds the risk limit")
+	ErrTooManyOpenOrders       = errors.New("open order count exceeds the risk limit")
+	ErrPriceOutsideBand        = errors.New("order price is outside of the allowed band around the mid price")
+	ErrLeverageTooHigh         = errors.New("leverage exceeds the risk limit")
+	ErrOperatorShareRateTooLow = errors.New("shares change would take the operator share rate below the pool's MinOperatorShareRate")
+	ErrTooManyInvestedPools    = errors.New("account already invests in the max number of public pools")
+	// ErrInvalidTxRequest is returned by SignTxJSON for requests which can't be decoded
+	ErrInvalidTxRequest = types.ErrInvalidTxRequest
+)
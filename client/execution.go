@@ -0,0 +1,429 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+const (
+	defaultExecutionPollInterval = time.Second * 2
+	recentTradesLookupLimit      = 100
+	icebergOrderExpiry           = time.Hour * 24 * 28
+)
+
+type ExecutionStrategy uint8
+
+const (
+	// ExecutionTWAP sends an IOC child order every Duration / Slices, catching up on what previous slices didn't fill
+	ExecutionTWAP ExecutionStrategy = iota
+	// ExecutionPOV sends IOC child orders to keep the filled amount at ParticipationRate of the market volume
+	ExecutionPOV
+	// ExecutionIceberg keeps a single resting order of VisibleBaseAmount, replacing it once it's done
+	ExecutionIceberg
+)
+
+type ExecutionState uint8
+
+const (
+	ExecutionRunning ExecutionState = iota
+	ExecutionPaused
+	ExecutionCompleted
+	ExecutionCanceled
+)
+
+func (s ExecutionState) String() string {
+	switch s {
+	case ExecutionRunning:
+		return "running"
+	case ExecutionPaused:
+		return "paused"
+	case ExecutionCompleted:
+		return "completed"
+	case ExecutionCanceled:
+		return "canceled"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(s))
+	}
+}
+
+type ExecutionConfig struct {
+	Strategy    ExecutionStrategy
+	MarketIndex uint8
+	IsAsk       uint8
+	// TotalBaseAmount is the parent quantity to execute
+	TotalBaseAmount int64
+	// LimitPrice is the price of every child order, so no fill is worse than it
+	LimitPrice uint32
+
+	// Duration & Slices are used by ExecutionTWAP
+	Duration time.Duration
+	Slices   int
+	// ParticipationRate is used by ExecutionPOV, e.g. 0.1 to follow 10% of the market volume
+	ParticipationRate float64
+	// VisibleBaseAmount is used by ExecutionIceberg
+	VisibleBaseAmount int64
+
+	// PollInterval is how often fills & market volume are checked. Defaults to 2 seconds.
+	PollInterval time.Duration
+	// OnError is called from the execution goroutine when a child order can't be placed or fills can't be synced
+	OnError func(err error)
+}
+
+type ExecutionProgress struct {
+	State            ExecutionState
+	FilledBaseAmount int64
+	// WorkingOrderID is the ID of the child order which is not done yet, if any. A child whose tx failed or expired
+	// is done once synced, so the schedule moves on to the next one.
+	WorkingOrderID string
+	ChildOrders    int
+}
+
+// Execution slices a parent quantity into child orders, placed & tracked through an OrderManager.
+// Child orders are tagged with the execution ID; they shouldn't be pruned from the OrderManager while the execution runs.
+type Execution struct {
+	id     string
+	orders *OrderManager
+	config ExecutionConfig
+	market *OrderBookDetail
+
+	mu       sync.Mutex
+	state    ExecutionState
+	started  time.Time
+	children []string
+	// last TWAP slice a child order was sent for, so each slice sends at most one
+	lastSlice int64
+	// market volume & trades seen since the start, used by ExecutionPOV
+	marketVolume int64
+	seenTrades   map[int64]bool
+
+	cancel     chan struct{}
+	cancelOnce sync.Once
+	done       chan struct{}
+}
+
+func NewExecution(id string, orders *OrderManager, config ExecutionConfig) (*Execution, error) {
+	if config.TotalBaseAmount < txtypes.MinOrderBaseAmount || config.TotalBaseAmount > txtypes.MaxOrderBaseAmount {
+		return nil, txtypes.ErrBaseAmountTooLow
+	}
+	if config.LimitPrice < txtypes.MinOrderPrice {
+		return nil, txtypes.ErrPriceTooLow
+	}
+	if config.IsAsk != 0 && config.IsAsk != 1 {
+		return nil, txtypes.ErrIsAskInvalid
+	}
+	switch config.Strategy {
+	case ExecutionTWAP:
+		if config.Duration <= 0 || config.Slices <= 0 {
+			return nil, fmt.Errorf("TWAP execution needs a positive Duration and Slices")
+		}
+	case ExecutionPOV:
+		if config.ParticipationRate <= 0 || config.ParticipationRate > 1 {
+			return nil, fmt.Errorf("POV execution needs a ParticipationRate between 0 and 1")
+		}
+	case ExecutionIceberg:
+		if config.VisibleBaseAmount < txtypes.MinOrderBaseAmount || config.VisibleBaseAmount > config.TotalBaseAmount {
+			return nil, fmt.Errorf("iceberg execution needs a VisibleBaseAmount between %v and the total amount", txtypes.MinOrderBaseAmount)
+		}
+	default:
+		return nil, fmt.Errorf("unknown execution strategy %v", config.Strategy)
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultExecutionPollInterval
+	}
+
+	market, err := orders.txClient.market(config.MarketIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Execution{
+		id:         id,
+		orders:     orders,
+		config:     config,
+		market:     market,
+		seenTrades: make(map[int64]bool),
+		cancel:     make(chan struct{}),
+		done:       make(chan struct{}),
+	}, nil
+}
+
+// Start runs the execution in the background until it's completed, canceled or ctx is done.
+// It does nothing if the execution was already started or canceled.
+func (e *Execution) Start(ctx context.Context) {
+	e.mu.Lock()
+	if !e.started.IsZero() || e.state == ExecutionCanceled {
+		e.mu.Unlock()
+		return
+	}
+	e.started = time.Now()
+	e.mu.Unlock()
+
+	go e.run(ctx)
+}
+
+func (e *Execution) run(ctx context.Context) {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		if completed := e.step(); completed {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			e.stop(ExecutionCanceled)
+			return
+		case <-e.cancel:
+			e.stop(ExecutionCanceled)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// step syncs fills and places the next child order if needed. Returns true once the execution is completed.
+func (e *Execution) step() bool {
+	if err := e.orders.Sync(); err != nil {
+		e.onError(fmt.Errorf("failed to sync child orders. err: %w", err))
+		return false
+	}
+
+	progress := e.Progress()
+	remaining := e.config.TotalBaseAmount - progress.FilledBaseAmount
+	if remaining <= 0 {
+		e.setState(ExecutionCompleted)
+		return true
+	}
+	if e.config.Strategy == ExecutionTWAP && time.Since(e.startedAt()) > e.config.Duration && progress.WorkingOrderID == "" {
+		// the last slice is done, whatever it didn't fill is left unexecuted
+		e.setState(ExecutionCompleted)
+		return true
+	}
+	if progress.State != ExecutionRunning || progress.WorkingOrderID != "" {
+		return false
+	}
+
+	var baseAmount, slice int64
+	var err error
+	switch e.config.Strategy {
+	case ExecutionTWAP:
+		baseAmount, slice = e.twapSliceAmount(progress.FilledBaseAmount)
+	case ExecutionPOV:
+		baseAmount, err = e.povSliceAmount(progress.FilledBaseAmount)
+	case ExecutionIceberg:
+		baseAmount = e.config.VisibleBaseAmount
+	}
+	if err != nil {
+		e.onError(err)
+		return false
+	}
+
+	baseAmount = min(baseAmount, remaining)
+	if baseAmount < txtypes.MinOrderBaseAmount {
+		return false
+	}
+	placed, err := e.placeChild(baseAmount, progress.ChildOrders)
+	if err != nil {
+		e.onError(err)
+	}
+	if placed && slice != 0 {
+		// a failed placement is retried during the same slice
+		e.mu.Lock()
+		e.lastSlice = slice
+		e.mu.Unlock()
+	}
+	return false
+}
+
+// twapSliceAmount returns how much is needed to catch up with the schedule of the current slice along with the slice,
+// or 0 if a child order was already placed during it
+func (e *Execution) twapSliceAmount(filled int64) (int64, int64) {
+	sliceDuration := e.config.Duration / time.Duration(e.config.Slices)
+	slice := int64(time.Since(e.startedAt())/sliceDuration) + 1
+	slice = min(slice, int64(e.config.Slices))
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if slice <= e.lastSlice {
+		return 0, 0
+	}
+
+	target := e.config.TotalBaseAmount * slice / int64(e.config.Slices)
+	return target - filled, slice
+}
+
+// povSliceAmount adds the trades made by others since the last call to the market volume
+// and returns how much is needed to keep up with the participation rate
+func (e *Execution) povSliceAmount(filled int64) (int64, error) {
+	trades, err := e.orders.txClient.HTTP().GetRecentTrades(e.config.MarketIndex, recentTradesLookupLimit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get recent trades. err: %w", err)
+	}
+
+	accountIndex := e.orders.txClient.GetAccountIndex()
+	startedAt := e.startedAt().UnixMilli()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, trade := range trades {
+		if e.seenTrades[trade.TradeId] || trade.Timestamp < startedAt {
+			continue
+		}
+		e.seenTrades[trade.TradeId] = true
+		// our own fills would otherwise push the target up
+		if trade.AskAccountIndex == accountIndex || trade.BidAccountIndex == accountIndex {
+			continue
+		}
+		size, err := parseDecimal(trade.Size)
+		if err != nil {
+			return 0, err
+		}
+		e.marketVolume += toTicks(size, e.market.SizeDecimals)
+	}
+
+	target := int64(float64(e.marketVolume) * e.config.ParticipationRate)
+	return target - filled, nil
+}
+
+// placeChild reports whether Lighter accepted the child order, which can be the case even if an error is returned
+func (e *Execution) placeChild(baseAmount int64, n int) (bool, error) {
+	order := &types.CreateOrderTxReq{
+		MarketIndex: e.config.MarketIndex,
+		BaseAmount:  baseAmount,
+		Price:       e.config.LimitPrice,
+		IsAsk:       e.config.IsAsk,
+		Type:        txtypes.LimitOrder,
+		TimeInForce: txtypes.ImmediateOrCancel,
+		OrderExpiry: txtypes.NilOrderExpiry,
+	}
+	if e.config.Strategy == ExecutionIceberg {
+		order.TimeInForce = txtypes.GoodTillTime
+		order.OrderExpiry = time.Now().Add(icebergOrderExpiry).UnixMilli()
+	}
+
+	id := fmt.Sprintf("%s-%d", e.id, n)
+	e.mu.Lock()
+	e.children = append(e.children, id)
+	e.mu.Unlock()
+
+	managed, err := e.orders.PlaceOrder(id, order, nil, e.id)
+	placed := managed != nil && managed.State != OrderStateRejected
+	if err != nil {
+		return placed, fmt.Errorf("failed to place child order %s. err: %w", id, err)
+	}
+	return placed, nil
+}
+
+// Progress sums the fills of the child orders, as last synced by the OrderManager
+func (e *Execution) Progress() ExecutionProgress {
+	e.mu.Lock()
+	children := append([]string(nil), e.children...)
+	state := e.state
+	e.mu.Unlock()
+
+	progress := ExecutionProgress{State: state, ChildOrders: len(children)}
+	for _, id := range children {
+		order, ok := e.orders.Order(id)
+		if !ok {
+			continue
+		}
+		if !order.State.IsTerminal() {
+			progress.WorkingOrderID = id
+		}
+		filled, err := parseDecimal(order.FilledBaseAmount)
+		if err != nil {
+			continue
+		}
+		progress.FilledBaseAmount += toTicks(filled, e.market.SizeDecimals)
+	}
+	return progress
+}
+
+// Pause stops placing child orders and cancels the working one. Resume continues where it left off.
+func (e *Execution) Pause() error {
+	e.mu.Lock()
+	if e.state != ExecutionRunning {
+		e.mu.Unlock()
+		return fmt.Errorf("execution is %v", e.state)
+	}
+	e.state = ExecutionPaused
+	e.mu.Unlock()
+
+	return e.cancelWorking()
+}
+
+func (e *Execution) Resume() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.state != ExecutionPaused {
+		return fmt.Errorf("execution is %v", e.state)
+	}
+	e.state = ExecutionRunning
+	return nil
+}
+
+// Cancel stops the execution and cancels the working child order. An execution canceled before being started never runs.
+func (e *Execution) Cancel() {
+	e.mu.Lock()
+	if e.started.IsZero() {
+		if e.state != ExecutionCanceled {
+			e.state = ExecutionCanceled
+			close(e.done)
+		}
+		e.mu.Unlock()
+		return
+	}
+	e.mu.Unlock()
+
+	e.cancelOnce.Do(func() {
+		close(e.cancel)
+	})
+	<-e.done
+}
+
+func (e *Execution) Done() <-chan struct{} {
+	return e.done
+}
+
+func (e *Execution) stop(state ExecutionState) {
+	e.setState(state)
+	if err := e.cancelWorking(); err != nil {
+		e.onError(err)
+	}
+}
+
+func (e *Execution) cancelWorking() error {
+	result, err := e.orders.CancelTagged(e.id, nil)
+	if err != nil {
+		return fmt.Errorf("failed to cancel child orders. err: %w", err)
+	}
+	if failed := result.Failed(); len(failed) != 0 {
+		return fmt.Errorf("failed to cancel child order %v. err: %w", failed[0].ClientOrderIndex, failed[0].Err)
+	}
+	return nil
+}
+
+func (e *Execution) setState(state ExecutionState) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.state = state
+}
+
+func (e *Execution) startedAt() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.started
+}
+
+func (e *Execution) onError(err error) {
+	if e.config.OnError != nil {
+		e.config.OnError(err)
+	}
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCancelBeforeStart(t *testing.T) {
+	e := &Execution{
+		id:     "twap",
+		config: ExecutionConfig{Strategy: ExecutionTWAP, Duration: time.Minute, Slices: 1, PollInterval: time.Millisecond},
+		cancel: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	canceled := make(chan struct{})
+	go func() {
+		e.Cancel()
+		e.Cancel()
+		close(canceled)
+	}()
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("Cancel before Start blocked")
+	}
+
+	// the orders are nil, so running the execution would panic
+	e.Start(context.Background())
+	select {
+	case <-e.Done():
+	default:
+		t.Fatal("expected Done to be closed")
+	}
+	if state := e.Progress().State; state != ExecutionCanceled {
+		t.Fatalf("expected canceled, got %v", state)
+	}
+}
+
+func TestExecutionMovesOnWhenChildTxFails(t *testing.T) {
+	var status atomic.Int64
+	status.Store(TxStatusPending)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/nextNonce":
+			w.Write([]byte(`{"code":200,"nonce":1}`))
+		case "/api/v1/sendTx":
+			w.Write([]byte(`{"code":200,"tx_hash":"0xaa"}`))
+		case "/api/v1/tx":
+			fmt.Fprintf(w, `{"code":200,"hash":"0xaa","status":%v}`, status.Load())
+		case "/api/v1/accountActiveOrders", "/api/v1/accountInactiveOrders":
+			w.Write([]byte(`{"code":200,"orders":[]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	txClient := newTestTxClient(t, NewHTTPClient(server.URL))
+	orders, err := NewOrderManager(txClient, filepath.Join(t.TempDir(), "orders"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer orders.Close()
+
+	e := &Execution{
+		id:     "iceberg",
+		orders: orders,
+		config: ExecutionConfig{Strategy: ExecutionIceberg, TotalBaseAmount: 1000, VisibleBaseAmount: 100, LimitPrice: 1000},
+		market: &OrderBookDetail{},
+		state:  ExecutionRunning,
+	}
+	e.config.OnError = func(err error) {
+		t.Error(err)
+	}
+
+	e.step()
+	e.step()
+	if progress := e.Progress(); progress.ChildOrders != 1 || progress.WorkingOrderID != "iceberg-0" {
+		t.Fatalf("expected the first child to be working while its tx is pending, got %+v", progress)
+	}
+
+	// the first child never reaches the book
+	status.Store(TxStatusFailed)
+	e.step()
+	if progress := e.Progress(); progress.ChildOrders != 2 || progress.WorkingOrderID != "iceberg-1" {
+		t.Fatalf("expected a second child once the first one's tx failed, got %+v", progress)
+	}
+	if child, _ := orders.Order("iceberg-0"); child.State != OrderStateRejected {
+		t.Fatalf("expected the first child to be rejected, got %v", child.State)
+	}
+}
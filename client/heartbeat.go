@@ -0,0 +1,161 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+const (
+	// schedule the cancel a bit past the minimum period, so a heartbeat signed right at the minimum isn't rejected
+	defaultHeartbeatTimeout = time.Duration(txtypes.MinOrderCancelAllPeriod)*time.Millisecond + time.Minute
+)
+
+type HeartbeatConfig struct {
+	// Timeout is how far in the future every heartbeat schedules the cancel all.
+	// It must be between MinOrderCancelAllPeriod & MaxOrderCancelAllPeriod. Defaults to 6 minutes.
+	Timeout time.Duration
+	// Interval between heartbeats, needs to be lower than Timeout. Defaults to Timeout / 5.
+	Interval time.Duration
+	// OnError is called from the heartbeat goroutine whenever a heartbeat fails
+	OnError func(err error)
+}
+
+// Heartbeat is a dead man's switch: it keeps pushing a scheduled cancel all into the future,
+// so every resting order gets canceled if the process stops sending heartbeats.
+type Heartbeat struct {
+	txClient *TxClient
+	config   HeartbeatConfig
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+
+	mu                sync.Mutex
+	lastBeat          time.Time
+	lastErr           error
+	consecutiveErrors int
+}
+
+// StartHeartbeat sends the first heartbeat right away, returning its error if it fails, and keeps sending them in the background until Stop is called.
+//
+// Every heartbeat fetches the next nonce of the client's API key from Lighter, so signing other txs with the same API key
+// at the same time can produce duplicate nonces. Start the heartbeat on a TxClient of an API key used for nothing else:
+// the scheduled cancel all still covers every order of the account, whichever API key placed it.
+func (c *TxClient) StartHeartbeat(config HeartbeatConfig) (*Heartbeat, error) {
+	if config.Timeout == 0 {
+		config.Timeout = defaultHeartbeatTimeout
+	}
+	if config.Interval == 0 {
+		config.Interval = config.Timeout / 5
+	}
+	if config.Timeout.Milliseconds() < txtypes.MinOrderCancelAllPeriod || config.Timeout.Milliseconds() > txtypes.MaxOrderCancelAllPeriod {
+		return nil, fmt.Errorf("heartbeat timeout should be between %v and %v ms", txtypes.MinOrderCancelAllPeriod, txtypes.MaxOrderCancelAllPeriod)
+	}
+	if config.Interval <= 0 || config.Interval >= config.Timeout {
+		return nil, fmt.Errorf("heartbeat interval should be larger than 0 and lower than the timeout")
+	}
+
+	h := &Heartbeat{
+		txClient: c,
+		config:   config,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if err := h.beat(); err != nil {
+		return nil, err
+	}
+
+	go h.run()
+	return h, nil
+}
+
+func (h *Heartbeat) run() {
+	defer close(h.done)
+
+	ticker := time.NewTicker(h.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+			if err := h.beat(); err != nil && h.config.OnError != nil {
+				h.config.OnError(err)
+			}
+		}
+	}
+}
+
+func (h *Heartbeat) beat() error {
+	err := h.sendCancelAll(&types.CancelAllOrdersTxReq{
+		TimeInForce: txtypes.ScheduledCancelAll,
+		Time:        time.Now().Add(h.config.Timeout).UnixMilli(),
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastErr = err
+	if err != nil {
+		h.consecutiveErrors++
+		return err
+	}
+	h.consecutiveErrors = 0
+	h.lastBeat = time.Now()
+	return nil
+}
+
+func (h *Heartbeat) sendCancelAll(tx *types.CancelAllOrdersTxReq) error {
+	txInfo, err := h.txClient.GetCancelAllOrdersTransaction(tx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to sign cancel all. err: %w", err)
+	}
+	if _, err := h.txClient.SendTx(txInfo); err != nil {
+		return fmt.Errorf("failed to send cancel all. err: %w", err)
+	}
+	return nil
+}
+
+// Stop ends the heartbeat and aborts the scheduled cancel all, so resting orders are kept on a clean shutdown
+func (h *Heartbeat) Stop() error {
+	stopped := false
+	h.stopOnce.Do(func() {
+		close(h.stop)
+		stopped = true
+	})
+	if !stopped {
+		return fmt.Errorf("heartbeat already stopped")
+	}
+	<-h.done
+
+	return h.sendCancelAll(&types.CancelAllOrdersTxReq{
+		TimeInForce: txtypes.AbortScheduledCancelAll,
+	})
+}
+
+// LastBeat returns when the cancel all was last successfully pushed into the future
+func (h *Heartbeat) LastBeat() time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.lastBeat
+}
+
+// Deadline returns when resting orders get canceled if no further heartbeat succeeds
+func (h *Heartbeat) Deadline() time.Time {
+	return h.LastBeat().Add(h.config.Timeout)
+}
+
+func (h *Heartbeat) LastError() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.lastErr
+}
+
+func (h *Heartbeat) ConsecutiveErrors() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.consecutiveErrors
+}
@@ -26,10 +26,16 @@ var (
 	}
 )
 
+// BeforeSendHook is called with the locally computed tx hash right before a tx is sent.
+// Returning an error aborts the send, e.g. when the tx could not be journaled.
+type BeforeSendHook func(txType uint8, txInfo string, txHash string) error
+
 type HTTPClient struct {
 	endpoint            string
 	channelName         string
 	fatFingerProtection bool
+	verifyTxHash        bool
+	beforeSend          BeforeSendHook
 }
 
 func NewHTTPClient(baseUrl string) *HTTPClient {
@@ -47,3 +53,13 @@ func NewHTTPClient(baseUrl string) *HTTPClient {
 func (c *HTTPClient) SetFatFingerProtection(enabled bool) {
 	c.fatFingerProtection = enabled
 }
+
+// SetTxHashVerification makes SendRawTx compare the TxHash returned by Lighter against the signed hash,
+// returning ErrTxHashMismatch if they differ.
+func (c *HTTPClient) SetTxHashVerification(enabled bool) {
+	c.verifyTxHash = enabled
+}
+
+func (c *HTTPClient) SetBeforeSendHook(hook BeforeSendHook) {
+	c.beforeSend = hook
+}
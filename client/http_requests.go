@@ -76,42 +76,140 @@ func (c *HTTPClient) GetApiKey(accountIndex int64, apiKeyIndex uint8) (*AccountA
 }
 
 func (c *HTTPClient) SendRawTx(tx txtypes.TxInfo) (string, error) {
+	if tx.IsDryRun() {
+		return "", ErrDryRunTx
+	}
 	txType := tx.GetTxType()
 	txInfo, err := tx.GetTxInfo()
 	if err != nil {
 		return "", err
 	}
 
-	data := url.Values{"tx_type": {strconv.Itoa(int(txType))}, "tx_info": {txInfo}}
+	if c.beforeSend != nil {
+		if err := c.beforeSend(txType, txInfo, tx.GetTxHash()); err != nil {
+			return "", fmt.Errorf("before send hook failed. err: %w", err)
+		}
+	}
 
-	if c.fatFingerProtection == false {
-		data.Add("price_protection", "false")
+	data := sendTxForm(txType, txInfo, c.fatFingerProtection)
+
+	body, err := c.postL2HTTPForm("/api/v1/sendTx", data)
+	if err != nil {
+		return "", err
+	}
+	res := &TxHash{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return "", err
+	}
+
+	if c.verifyTxHash && !equalTxHash(res.TxHash, tx.GetTxHash()) {
+		// the tx was still accepted, so the server hash is returned alongside the error
+		return res.TxHash, fmt.Errorf("%w. signed: %s received: %s", ErrTxHashMismatch, tx.GetTxHash(), res.TxHash)
+	}
+
+	return res.TxHash, nil
+}
+
+// SendRawTxBatch sends the txs in a single request. They're accepted or rejected as a whole.
+func (c *HTTPClient) SendRawTxBatch(txs []txtypes.TxInfo) ([]string, error) {
+	txTypes := make([]uint8, 0, len(txs))
+	txInfos := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		if tx.IsDryRun() {
+			return nil, ErrDryRunTx
+		}
+		txInfo, err := tx.GetTxInfo()
+		if err != nil {
+			return nil, err
+		}
+		if c.beforeSend != nil {
+			if err := c.beforeSend(tx.GetTxType(), txInfo, tx.GetTxHash()); err != nil {
+				return nil, fmt.Errorf("before send hook failed. err: %w", err)
+			}
+		}
+		txTypes = append(txTypes, tx.GetTxType())
+		txInfos = append(txInfos, txInfo)
+	}
+
+	data, err := sendTxBatchForm(txTypes, txInfos, c.fatFingerProtection)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.postL2HTTPForm("/api/v1/sendTxBatch", data)
+	if err != nil {
+		return nil, err
+	}
+	res := &TxHashes{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, err
+	}
+	if len(res.TxHashes) != len(txs) {
+		return res.TxHashes, fmt.Errorf("expected %v tx hashes but got %v", len(txs), len(res.TxHashes))
+	}
+
+	if c.verifyTxHash {
+		for i, tx := range txs {
+			if !equalTxHash(res.TxHashes[i], tx.GetTxHash()) {
+				return res.TxHashes, fmt.Errorf("%w. signed: %s received: %s", ErrTxHashMismatch, tx.GetTxHash(), res.TxHashes[i])
+			}
+		}
 	}
 
-	req, _ := http.NewRequest("POST", c.endpoint+"/api/v1/sendTx", strings.NewReader(data.Encode()))
+	return res.TxHashes, nil
+}
+
+func (c *HTTPClient) postL2HTTPForm(path string, data url.Values) ([]byte, error) {
+	req, _ := http.NewRequest("POST", c.endpoint+path, strings.NewReader(data.Encode()))
 	req.Header.Set("Channel-Name", c.channelName)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return "", errors.New(string(body))
+		return nil, errors.New(string(body))
 	}
 	if err = c.parseResultStatus(body); err != nil {
-		return "", err
+		return nil, err
 	}
-	res := &TxHash{}
-	if err := json.Unmarshal(body, res); err != nil {
-		return "", err
+	return body, nil
+}
+
+func sendTxBatchForm(txTypes []uint8, txInfos []string, priceProtection bool) (url.Values, error) {
+	// a []uint8 would be marshalled as base64, so the types are converted to ints first
+	types := make([]int, 0, len(txTypes))
+	for _, txType := range txTypes {
+		types = append(types, int(txType))
+	}
+	typesJson, err := json.Marshal(types)
+	if err != nil {
+		return nil, err
+	}
+	infosJson, err := json.Marshal(txInfos)
+	if err != nil {
+		return nil, err
 	}
 
-	return res.TxHash, nil
+	data := url.Values{"tx_types": {string(typesJson)}, "tx_infos": {string(infosJson)}}
+	if priceProtection == false {
+		data.Add("price_protection", "false")
+	}
+	return data, nil
+}
+
+func sendTxForm(txType uint8, txInfo string, priceProtection bool) url.Values {
+	data := url.Values{"tx_type": {strconv.Itoa(int(txType))}, "tx_info": {txInfo}}
+
+	if priceProtection == false {
+		data.Add("price_protection", "false")
+	}
+	return data
 }
 
 func (c *HTTPClient) GetTransferFeeInfo(accountIndex, toAccountIndex int64, auth string) (*TransferFeeInfo, error) {
@@ -126,3 +224,102 @@ func (c *HTTPClient) GetTransferFeeInfo(accountIndex, toAccountIndex int64, auth
 	}
 	return result, nil
 }
+
+func (c *HTTPClient) GetTxByHash(txHash string) (*Tx, error) {
+	result := &Tx{}
+	err := c.getAndParseL2HTTPResponse("api/v1/tx", map[string]any{"by": "hash", "value": txHash}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *HTTPClient) GetActiveOrders(accountIndex int64, marketIndex uint8, auth string) ([]*Order, error) {
+	result := &Orders{}
+	err := c.getAndParseL2HTTPResponse("api/v1/accountActiveOrders", map[string]any{
+		"account_index": accountIndex,
+		"market_id":     marketIndex,
+		"auth":          auth,
+	}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Orders, nil
+}
+
+func (c *HTTPClient) GetInactiveOrders(accountIndex int64, marketIndex uint8, limit int, auth string) ([]*Order, error) {
+	result := &Orders{}
+	err := c.getAndParseL2HTTPResponse("api/v1/accountInactiveOrders", map[string]any{
+		"account_index": accountIndex,
+		"market_id":     marketIndex,
+		"limit":         limit,
+		"auth":          auth,
+	}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Orders, nil
+}
+
+func (c *HTTPClient) GetOrderBookDetail(marketIndex uint8) (*OrderBookDetail, error) {
+	result := &OrderBookDetails{}
+	err := c.getAndParseL2HTTPResponse("api/v1/orderBookDetails", map[string]any{"market_id": marketIndex}, result)
+	if err != nil {
+		return nil, err
+	}
+	for _, detail := range result.OrderBookDetails {
+		if detail.MarketIndex == marketIndex {
+			return detail, nil
+		}
+	}
+	return nil, fmt.Errorf("market %v not found", marketIndex)
+}
+
+func (c *HTTPClient) GetOrderBookOrders(marketIndex uint8, limit int) (*OrderBookOrders, error) {
+	result := &OrderBookOrders{}
+	err := c.getAndParseL2HTTPResponse("api/v1/orderBookOrders", map[string]any{"market_id": marketIndex, "limit": limit}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *HTTPClient) GetAccount(accountIndex int64) (*Account, error) {
+	result := &Accounts{}
+	err := c.getAndParseL2HTTPResponse("api/v1/account", map[string]any{"by": "index", "value": accountIndex}, result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Accounts) == 0 {
+		return nil, fmt.Errorf("account %v not found", accountIndex)
+	}
+	return result.Accounts[0], nil
+}
+
+func (c *HTTPClient) GetRecentTrades(marketIndex uint8, limit int) ([]*Trade, error) {
+	result := &Trades{}
+	err := c.getAndParseL2HTTPResponse("api/v1/recentTrades", map[string]any{"market_id": marketIndex, "limit": limit}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Trades, nil
+}
+
+// GetAccountsByL1Address returns the master account & sub accounts owned by the L1 address
+func (c *HTTPClient) GetAccountsByL1Address(l1Address string) ([]*Account, error) {
+	result := &AccountsByL1Address{}
+	err := c.getAndParseL2HTTPResponse("api/v1/accountsByL1Address", map[string]any{"l1_address": l1Address}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result.SubAccounts, nil
+}
+
+func (c *HTTPClient) GetTransferHistory(accountIndex int64, auth string) ([]*TransferHistoryItem, error) {
+	result := &TransferHistory{}
+	err := c.getAndParseL2HTTPResponse("api/v1/transfer/history", map[string]any{"account_index": accountIndex, "auth": auth}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Transfers, nil
+}
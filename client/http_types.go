@@ -31,7 +31,188 @@ type TxHash struct {
 	TxHash string `json:"tx_hash,example=0x70997970C51812dc3A010C7d01b50e0d17dc79C8"`
 }
 
+type TxHashes struct {
+	ResultCode
+	TxHashes []string `json:"tx_hash"`
+}
+
 type TransferFeeInfo struct {
 	ResultCode
 	TransferFee int64 `json:"transfer_fee_usdc"`
 }
+
+// Tx statuses reported by api/v1/tx
+const (
+	TxStatusFailed   = 0
+	TxStatusPending  = 1
+	TxStatusExecuted = 2
+)
+
+type Tx struct {
+	ResultCode
+	Hash         string `json:"hash"`
+	Type         uint8  `json:"type"`
+	Info         string `json:"info"`
+	EventInfo    string `json:"event_info"`
+	Status       int64  `json:"status"`
+	AccountIndex int64  `json:"account_index"`
+	Nonce        int64  `json:"nonce"`
+	ExpireAt     int64  `json:"expire_at"`
+	BlockHeight  int64  `json:"block_height"`
+	QueuedAt     int64  `json:"queued_at"`
+	ExecutedAt   int64  `json:"executed_at"`
+}
+
+// Order amounts and prices are returned as decimal strings, scaled by the market's size & price decimals
+type Order struct {
+	OrderIndex          int64  `json:"order_index"`
+	ClientOrderIndex    int64  `json:"client_order_index"`
+	MarketIndex         uint8  `json:"market_index"`
+	OwnerAccountIndex   int64  `json:"owner_account_index"`
+	InitialBaseAmount   string `json:"initial_base_amount"`
+	RemainingBaseAmount string `json:"remaining_base_amount"`
+	FilledBaseAmount    string `json:"filled_base_amount"`
+	FilledQuoteAmount   string `json:"filled_quote_amount"`
+	Price               string `json:"price"`
+	IsAsk               bool   `json:"is_ask"`
+	Type                string `json:"type"`
+	TimeInForce         string `json:"time_in_force"`
+	ReduceOnly          bool   `json:"reduce_only"`
+	TriggerPrice        string `json:"trigger_price"`
+	OrderExpiry         int64  `json:"order_expiry"`
+	Status              string `json:"status"`
+	Nonce               int64  `json:"nonce"`
+	Timestamp           int64  `json:"timestamp"`
+}
+
+type Orders struct {
+	ResultCode
+	Orders []*Order `json:"orders"`
+}
+
+type OrderBookDetail struct {
+	Symbol                       string  `json:"symbol"`
+	MarketIndex                  uint8   `json:"market_id"`
+	Status                       string  `json:"status"`
+	MinBaseAmount                string  `json:"min_base_amount"`
+	MinQuoteAmount               string  `json:"min_quote_amount"`
+	SizeDecimals                 uint8   `json:"size_decimals"`
+	PriceDecimals                uint8   `json:"price_decimals"`
+	DefaultInitialMarginFraction uint16  `json:"default_initial_margin_fraction"`
+	MinInitialMarginFraction     uint16  `json:"min_initial_margin_fraction"`
+	MaintenanceMarginFraction    uint16  `json:"maintenance_margin_fraction"`
+	LastTradePrice               float64 `json:"last_trade_price"`
+}
+
+type OrderBookDetails struct {
+	ResultCode
+	OrderBookDetails []*OrderBookDetail `json:"order_book_details"`
+}
+
+type SimpleOrder struct {
+	OrderIndex          int64  `json:"order_index"`
+	OwnerAccountIndex   int64  `json:"owner_account_index"`
+	RemainingBaseAmount string `json:"remaining_base_amount"`
+	Price               string `json:"price"`
+}
+
+type OrderBookOrders struct {
+	ResultCode
+	TotalAsks int64          `json:"total_asks"`
+	Asks      []*SimpleOrder `json:"asks"`
+	TotalBids int64          `json:"total_bids"`
+	Bids      []*SimpleOrder `json:"bids"`
+}
+
+type AccountPosition struct {
+	MarketIndex           uint8  `json:"market_id"`
+	Symbol                string `json:"symbol"`
+	InitialMarginFraction string `json:"initial_margin_fraction"` // percentage, e.g. "10.00" for 10x
+	OpenOrderCount        int64  `json:"open_order_count"`
+	Sign                  int32  `json:"sign"` // 1 for long, -1 for short
+	Position              string `json:"position"`
+	AvgEntryPrice         string `json:"avg_entry_price"`
+	PositionValue         string `json:"position_value"`
+	UnrealizedPnl         string `json:"unrealized_pnl"`
+	LiquidationPrice      string `json:"liquidation_price"`
+	MarginMode            uint8  `json:"margin_mode"`
+	AllocatedMargin       string `json:"allocated_margin"`
+}
+
+type Account struct {
+	AccountIndex      int64              `json:"index"`
+	L1Address         string             `json:"l1_address"`
+	AccountType       uint8              `json:"account_type"`
+	Status            int32              `json:"status"`
+	Collateral        string             `json:"collateral"`
+	AvailableBalance  string             `json:"available_balance"`
+	TotalAssetValue   string             `json:"total_asset_value"`
+	TotalOrderCount   int64              `json:"total_order_count"`
+	PendingOrderCount int64              `json:"pending_order_count"`
+	Positions         []*AccountPosition `json:"positions"`
+	// PoolInfo is only set for public pool accounts
+	PoolInfo *PublicPoolInfo    `json:"pool_info"`
+	Shares   []*PublicPoolShare `json:"shares"`
+}
+
+type PublicPoolInfo struct {
+	Status               uint8  `json:"status"`
+	OperatorFee          string `json:"operator_fee"`            // percentage, e.g. "10.00" for 10%
+	MinOperatorShareRate string `json:"min_operator_share_rate"` // percentage
+	TotalShares          int64  `json:"total_shares"`
+	OperatorShares       int64  `json:"operator_shares"`
+	// OperatorAccountIndex is the account which created & operates the pool
+	OperatorAccountIndex int64 `json:"operator_account_index"`
+}
+
+// PublicPoolShare is a position of an account in a public pool
+type PublicPoolShare struct {
+	PublicPoolIndex int64  `json:"public_pool_index"`
+	SharesAmount    int64  `json:"shares_amount"`
+	EntryUSDC       string `json:"entry_usdc"`
+}
+
+type Accounts struct {
+	ResultCode
+	Accounts []*Account `json:"accounts"`
+}
+
+type Trade struct {
+	TradeId         int64  `json:"trade_id"`
+	TxHash          string `json:"tx_hash"`
+	MarketIndex     uint8  `json:"market_id"`
+	Size            string `json:"size"`
+	Price           string `json:"price"`
+	AskAccountIndex int64  `json:"ask_account_id"`
+	BidAccountIndex int64  `json:"bid_account_id"`
+	IsMakerAsk      bool   `json:"is_maker_ask"`
+	Timestamp       int64  `json:"timestamp"`
+}
+
+type Trades struct {
+	ResultCode
+	Trades []*Trade `json:"trades"`
+}
+
+type AccountsByL1Address struct {
+	ResultCode
+	L1Address   string     `json:"l1_address"`
+	SubAccounts []*Account `json:"sub_accounts"`
+}
+
+type TransferHistoryItem struct {
+	Id               string `json:"id"`
+	Amount           string `json:"amount"`
+	Timestamp        int64  `json:"timestamp"`
+	Type             string `json:"type"`
+	FromAccountIndex int64  `json:"from_account_index"`
+	ToAccountIndex   int64  `json:"to_account_index"`
+	TxHash           string `json:"tx_hash"`
+	// Memo is hex encoded
+	Memo string `json:"memo"`
+}
+
+type TransferHistory struct {
+	ResultCode
+	Transfers []*TransferHistoryItem `json:"transfers"`
+}
@@ -0,0 +1,339 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+const (
+	JournalSigned = "signed"
+	JournalSent   = "sent"
+
+	maxJournalLineSize = 1024 * 1024
+)
+
+// JournalEntry is a single line of the journal. Every tx produced by a TxClient gets a JournalSigned entry
+// and, once submitted through TxClient.SendTx, a JournalSent entry carrying the result of the send.
+type JournalEntry struct {
+	Kind               string
+	Time               int64 // unix millis
+	TxType             uint8
+	TxInfo             string `json:",omitempty"`
+	TxHash             string
+	AccountIndex       int64
+	ApiKeyIndex        uint8
+	Nonce              int64
+	ExpiredAt          int64
+	ClientOrderIndexes []int64 `json:",omitempty"`
+	SentTxHash         string  `json:",omitempty"`
+	SendErr            string  `json:",omitempty"`
+}
+
+type NonceGap struct {
+	AccountIndex int64
+	ApiKeyIndex  uint8
+	From         int64 // first missing nonce
+	To           int64 // last missing nonce
+}
+
+// Journal is an append-only file of JSON lines. Each entry is fsynced before Append returns.
+type Journal struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenJournal opens the journal for appending, first truncating the torn line left by a crash in the middle of a write,
+// so the next entry doesn't end up on the same line
+func OpenJournal(path string) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := truncateTornLine(file); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to truncate torn journal line. err: %w", err)
+	}
+	return &Journal{file: file}, nil
+}
+
+// truncateTornLine truncates the file after its last newline
+func truncateTornLine(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	size := info.Size()
+	buf := make([]byte, 4096)
+	for end := size; end > 0; {
+		start := max(end-int64(len(buf)), 0)
+		chunk := buf[:end-start]
+		if _, err := file.ReadAt(chunk, start); err != nil {
+			return err
+		}
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			return truncate(file, size, start+int64(i)+1)
+		}
+		end = start
+	}
+	return truncate(file, size, 0)
+}
+
+func truncate(file *os.File, size int64, newSize int64) error {
+	if newSize == size {
+		return nil
+	}
+	if err := file.Truncate(newSize); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+func (j *Journal) Append(entry *JournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err := j.file.Write(line); err != nil {
+		return err
+	}
+	return j.file.Sync()
+}
+
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.file.Close()
+}
+
+func (j *Journal) appendSigned(tx txtypes.TxInfo) error {
+	entry, err := newJournalEntry(JournalSigned, tx)
+	if err != nil {
+		return err
+	}
+	return j.Append(entry)
+}
+
+func (j *Journal) appendSent(tx txtypes.TxInfo, sentTxHash string, sendErr error) error {
+	entry, err := newJournalEntry(JournalSent, tx)
+	if err != nil {
+		return err
+	}
+	// the full tx info was already journaled when it was signed
+	entry.TxInfo = ""
+	entry.SentTxHash = sentTxHash
+	if sendErr != nil {
+		entry.SendErr = sendErr.Error()
+	}
+	return j.Append(entry)
+}
+
+func newJournalEntry(kind string, tx txtypes.TxInfo) (*JournalEntry, error) {
+	txInfo, err := tx.GetTxInfo()
+	if err != nil {
+		return nil, err
+	}
+	meta, err := parseTxMeta(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JournalEntry{
+		Kind:               kind,
+		Time:               time.Now().UnixMilli(),
+		TxType:             tx.GetTxType(),
+		TxInfo:             txInfo,
+		TxHash:             tx.GetTxHash(),
+		AccountIndex:       meta.accountIndex(),
+		ApiKeyIndex:        meta.ApiKeyIndex,
+		Nonce:              meta.Nonce,
+		ExpiredAt:          meta.ExpiredAt,
+		ClientOrderIndexes: clientOrderIndexes(tx),
+	}, nil
+}
+
+func clientOrderIndexes(tx txtypes.TxInfo) []int64 {
+	var ret []int64
+	switch tx := tx.(type) {
+	case *txtypes.L2CreateOrderTxInfo:
+		ret = append(ret, tx.ClientOrderIndex)
+	case *txtypes.L2CreateGroupedOrdersTxInfo:
+		for _, order := range tx.Orders {
+			ret = append(ret, order.ClientOrderIndex)
+		}
+	case *txtypes.L2ModifyOrderTxInfo:
+		ret = append(ret, tx.Index)
+	case *txtypes.L2CancelOrderTxInfo:
+		ret = append(ret, tx.Index)
+	}
+
+	// Index of Modify & Cancel can also be an order index
+	filtered := ret[:0]
+	for _, index := range ret {
+		if index >= txtypes.MinClientOrderIndex && index <= txtypes.MaxClientOrderIndex {
+			filtered = append(filtered, index)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+	return filtered
+}
+
+// CorruptJournalLine is a line of the journal which couldn't be parsed, e.g. the torn line left by a crash in the middle of a write
+type CorruptJournalLine struct {
+	Line int // 1-based
+	Err  error
+}
+
+// ReplayJournal calls fn for every entry, in the order they were written.
+// Lines which can't be parsed are skipped and returned, so a torn write doesn't prevent recovering the rest of the journal.
+func ReplayJournal(path string, fn func(entry *JournalEntry) error) ([]CorruptJournalLine, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var corrupt []CorruptJournalLine
+	reader := bufio.NewReaderSize(file, maxJournalLineSize)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 {
+				// whatever is left without a trailing newline was never fully written
+				corrupt = append(corrupt, CorruptJournalLine{Line: lineNumber, Err: fmt.Errorf("line is not terminated")})
+			}
+			return corrupt, nil
+		}
+		if err != nil {
+			return corrupt, err
+		}
+
+		entry := &JournalEntry{}
+		if err := json.Unmarshal(line, entry); err != nil {
+			corrupt = append(corrupt, CorruptJournalLine{Line: lineNumber, Err: err})
+			continue
+		}
+		if err := fn(entry); err != nil {
+			return corrupt, err
+		}
+	}
+}
+
+type JournalEntries []*JournalEntry
+
+// ReadJournal returns the entries of the journal, along with the lines skipped as they couldn't be parsed
+func ReadJournal(path string) (JournalEntries, []CorruptJournalLine, error) {
+	var entries JournalEntries
+	corrupt, err := ReplayJournal(path, func(entry *JournalEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return entries, corrupt, nil
+}
+
+func (entries JournalEntries) ByHash(txHash string) JournalEntries {
+	return entries.filter(func(entry *JournalEntry) bool {
+		return equalTxHash(entry.TxHash, txHash) || (entry.SentTxHash != "" && equalTxHash(entry.SentTxHash, txHash))
+	})
+}
+
+func (entries JournalEntries) ByNonce(accountIndex int64, apiKeyIndex uint8, nonce int64) JournalEntries {
+	return entries.filter(func(entry *JournalEntry) bool {
+		return entry.AccountIndex == accountIndex && entry.ApiKeyIndex == apiKeyIndex && entry.Nonce == nonce
+	})
+}
+
+func (entries JournalEntries) ByClientOrderIndex(clientOrderIndex int64) JournalEntries {
+	return entries.filter(func(entry *JournalEntry) bool {
+		for _, index := range entry.ClientOrderIndexes {
+			if index == clientOrderIndex {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Unsent returns the signed txs which have no matching JournalSent entry, e.g. because the process died before sending
+func (entries JournalEntries) Unsent() JournalEntries {
+	sent := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Kind == JournalSent {
+			sent[entry.TxHash] = true
+		}
+	}
+	return entries.filter(func(entry *JournalEntry) bool {
+		return entry.Kind == JournalSigned && !sent[entry.TxHash]
+	})
+}
+
+// NonceGaps returns the ranges of nonces missing between the lowest and highest journaled nonce of each (account, api key) pair
+func (entries JournalEntries) NonceGaps() []NonceGap {
+	type signer struct {
+		accountIndex int64
+		apiKeyIndex  uint8
+	}
+
+	nonces := make(map[signer][]int64)
+	for _, entry := range entries {
+		if entry.Kind != JournalSigned {
+			continue
+		}
+		key := signer{entry.AccountIndex, entry.ApiKeyIndex}
+		nonces[key] = append(nonces[key], entry.Nonce)
+	}
+
+	gaps := make([]NonceGap, 0)
+	for key, list := range nonces {
+		sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+		for i := 1; i < len(list); i++ {
+			if list[i] > list[i-1]+1 {
+				gaps = append(gaps, NonceGap{
+					AccountIndex: key.accountIndex,
+					ApiKeyIndex:  key.apiKeyIndex,
+					From:         list[i-1] + 1,
+					To:           list[i] - 1,
+				})
+			}
+		}
+	}
+	sort.Slice(gaps, func(i, j int) bool {
+		if gaps[i].AccountIndex != gaps[j].AccountIndex {
+			return gaps[i].AccountIndex < gaps[j].AccountIndex
+		}
+		if gaps[i].ApiKeyIndex != gaps[j].ApiKeyIndex {
+			return gaps[i].ApiKeyIndex < gaps[j].ApiKeyIndex
+		}
+		return gaps[i].From < gaps[j].From
+	})
+	return gaps
+}
+
+func (entries JournalEntries) filter(keep func(entry *JournalEntry) bool) JournalEntries {
+	var ret JournalEntries
+	for _, entry := range entries {
+		if keep(entry) {
+			ret = append(ret, entry)
+		}
+	}
+	return ret
+}
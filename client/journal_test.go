@@ -0,0 +1,81 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJournalRecoversFromTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+
+	journal, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.Append(&JournalEntry{Kind: JournalSigned, Nonce: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a crash in the middle of a write leaves a line without its newline
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(`{"Kind":"signed","No`); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	entries, corrupt, err := ReadJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || len(corrupt) != 1 || corrupt[0].Line != 2 {
+		t.Fatalf("expected 1 entry & the torn line 2 reported, got %v entries & %+v", len(entries), corrupt)
+	}
+
+	journal, err = OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.Append(&JournalEntry{Kind: JournalSigned, Nonce: 2}); err != nil {
+		t.Fatal(err)
+	}
+	journal.Close()
+
+	entries, corrupt, err = ReadJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrupt) != 0 {
+		t.Fatalf("expected the torn line to be truncated, got %+v", corrupt)
+	}
+	if len(entries) != 2 || entries[0].Nonce != 1 || entries[1].Nonce != 2 {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+}
+
+func TestReplayJournalSkipsCorruptLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	content := `{"Kind":"signed","Nonce":1}` + "\n" +
+		`{"Kind":"signed","No{"Kind":"signed","Nonce":2}` + "\n" +
+		`{"Kind":"signed","Nonce":3}` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, corrupt, err := ReadJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Nonce != 1 || entries[1].Nonce != 3 {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	if len(corrupt) != 1 || corrupt[0].Line != 2 {
+		t.Fatalf("expected line 2 to be reported, got %+v", corrupt)
+	}
+}
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+const (
+	usdcDecimals = 6
+)
+
+type LeverageSetting struct {
+	MarketIndex           uint8
+	Leverage              float64
+	InitialMarginFraction uint16
+	MarginMode            uint8
+	// AllocatedMargin is the USDC (6 decimals) allocated to an isolated position
+	AllocatedMargin int64
+}
+
+// LeverageToInitialMarginFraction converts a leverage multiple, e.g. 10 for 10x, to an InitialMarginFraction in MarginFractionTick units
+func LeverageToInitialMarginFraction(leverage float64) (uint16, error) {
+	if leverage < 1 {
+		return 0, fmt.Errorf("leverage should not be less than 1")
+	}
+	return uint16(math.Round(float64(txtypes.MarginFractionTick) / leverage)), nil
+}
+
+func InitialMarginFractionToLeverage(initialMarginFraction uint16) float64 {
+	if initialMarginFraction == 0 {
+		return 0
+	}
+	return float64(txtypes.MarginFractionTick) / float64(initialMarginFraction)
+}
+
+// GetLeverage returns the current leverage settings of the market, or nil if the account has none for it yet
+func (c *TxClient) GetLeverage(marketIndex uint8) (*LeverageSetting, error) {
+	if c.apiClient == nil {
+		return nil, fmt.Errorf("HTTPClient is nil, can't get leverage")
+	}
+	account, err := c.apiClient.GetAccount(c.accountIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, position := range account.Positions {
+		if position.MarketIndex != marketIndex {
+			continue
+		}
+		// the initial margin fraction is reported as a percentage
+		percentage, err := parseDecimal(position.InitialMarginFraction)
+		if err != nil {
+			return nil, err
+		}
+		allocatedMargin, err := parseDecimal(position.AllocatedMargin)
+		if err != nil {
+			return nil, err
+		}
+		initialMarginFraction := uint16(math.Round(percentage * float64(txtypes.MarginFractionTick) / 100))
+
+		return &LeverageSetting{
+			MarketIndex:           marketIndex,
+			Leverage:              InitialMarginFractionToLeverage(initialMarginFraction),
+			InitialMarginFraction: initialMarginFraction,
+			MarginMode:            position.MarginMode,
+			AllocatedMargin:       toTicks(allocatedMargin, usdcDecimals),
+		}, nil
+	}
+	return nil, nil
+}
+
+// SetLeverage sets the leverage multiple & margin mode (CrossMargin or IsolatedMargin) of the market.
+// The leverage is validated against the market's max leverage. Returns a nil result without sending anything
+// if the market already uses these settings.
+func (c *TxClient) SetLeverage(ctx context.Context, marketIndex uint8, leverage float64, marginMode uint8, ops *types.TransactOpts) (*TxResult, error) {
+	if marginMode != txtypes.CrossMargin && marginMode != txtypes.IsolatedMargin {
+		return nil, txtypes.ErrInvalidMarginMode
+	}
+	initialMarginFraction, err := LeverageToInitialMarginFraction(leverage)
+	if err != nil {
+		return nil, err
+	}
+
+	market, err := c.market(marketIndex)
+	if err != nil {
+		return nil, err
+	}
+	if initialMarginFraction < market.MinInitialMarginFraction {
+		return nil, fmt.Errorf("leverage %v is higher than the max leverage of market %v, which is %v", leverage, marketIndex, InitialMarginFractionToLeverage(market.MinInitialMarginFraction))
+	}
+
+	current, err := c.GetLeverage(marketIndex)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && current.InitialMarginFraction == initialMarginFraction && current.MarginMode == marginMode {
+		return nil, nil
+	}
+
+	txInfo, err := c.GetUpdateLeverageTransaction(&types.UpdateLeverageTxReq{
+		MarketIndex:           marketIndex,
+		InitialMarginFraction: initialMarginFraction,
+		MarginMode:            marginMode,
+	}, ops)
+	if err != nil {
+		return nil, err
+	}
+	return c.SendAndWait(ctx, txInfo)
+}
+
+// SetIsolatedMargin adds or removes USDC (6 decimals) from the isolated position of the market, so its allocated margin matches target.
+// Returns a nil result without sending anything if it already does.
+func (c *TxClient) SetIsolatedMargin(ctx context.Context, marketIndex uint8, target int64, ops *types.TransactOpts) (*TxResult, error) {
+	current, err := c.GetLeverage(marketIndex)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || current.MarginMode != txtypes.IsolatedMargin {
+		return nil, fmt.Errorf("market %v does not use isolated margin", marketIndex)
+	}
+
+	delta := target - current.AllocatedMargin
+	if delta == 0 {
+		return nil, nil
+	}
+	return c.updateIsolatedMargin(ctx, marketIndex, delta, ops)
+}
+
+// updateIsolatedMargin adds delta USDC to the isolated margin of the market, removing it if delta is negative
+func (c *TxClient) updateIsolatedMargin(ctx context.Context, marketIndex uint8, delta int64, ops *types.TransactOpts) (*TxResult, error) {
+	txInfo, err := c.getIsolatedMarginTransaction(marketIndex, delta, ops)
+	if err != nil {
+		return nil, err
+	}
+	return c.SendAndWait(ctx, txInfo)
+}
+
+func (c *TxClient) getIsolatedMarginTransaction(marketIndex uint8, delta int64, ops *types.TransactOpts) (*txtypes.L2UpdateMarginTxInfo, error) {
+	req := &types.UpdateMarginTxReq{
+		MarketIndex: marketIndex,
+		USDCAmount:  delta,
+		Direction:   txtypes.AddToIsolatedMargin,
+	}
+	if delta < 0 {
+		req.USDCAmount = -delta
+		req.Direction = txtypes.RemoveFromIsolatedMargin
+	}
+
+	return c.GetUpdateMarginTransaction(req, ops)
+}
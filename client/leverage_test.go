@@ -0,0 +1,62 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+func TestLeverageToInitialMarginFraction(t *testing.T) {
+	tests := []struct {
+		leverage              float64
+		initialMarginFraction uint16
+		err                   bool
+	}{
+		{leverage: 1, initialMarginFraction: 10_000},
+		{leverage: 3, initialMarginFraction: 3333},
+		{leverage: 10, initialMarginFraction: 1000},
+		{leverage: 20, initialMarginFraction: 500},
+		{leverage: 50, initialMarginFraction: 200},
+		{leverage: 0.5, err: true},
+		{leverage: 0, err: true},
+		{leverage: -10, err: true},
+	}
+	for _, tt := range tests {
+		initialMarginFraction, err := LeverageToInitialMarginFraction(tt.leverage)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("leverage %v: expected an error", tt.leverage)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("leverage %v: %v", tt.leverage, err)
+		}
+		if initialMarginFraction != tt.initialMarginFraction {
+			t.Fatalf("leverage %v: expected %v, got %v", tt.leverage, tt.initialMarginFraction, initialMarginFraction)
+		}
+		if leverage := InitialMarginFractionToLeverage(initialMarginFraction); leverage < tt.leverage*0.999 || leverage > tt.leverage*1.001 {
+			t.Fatalf("leverage %v: converted back to %v", tt.leverage, leverage)
+		}
+	}
+	if leverage := InitialMarginFractionToLeverage(0); leverage != 0 {
+		t.Fatalf("expected 0 leverage without initial margin fraction, got %v", leverage)
+	}
+}
+
+// the margin mode used to be dropped when converting the request, so isolated margin couldn't be set
+func TestUpdateLeverageKeepsMarginMode(t *testing.T) {
+	txClient := newTestTxClient(t, nil)
+	for _, marginMode := range []uint8{txtypes.CrossMargin, txtypes.IsolatedMargin} {
+		nonce := int64(1)
+		req := &types.UpdateLeverageTxReq{MarketIndex: 1, InitialMarginFraction: 1000, MarginMode: marginMode}
+		txInfo, err := txClient.GetUpdateLeverageTransaction(req, &types.TransactOpts{Nonce: &nonce})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if txInfo.MarginMode != marginMode || txInfo.InitialMarginFraction != 1000 || txInfo.MarketIndex != 1 {
+			t.Fatalf("margin mode %v: unexpected tx %+v", marginMode, txInfo)
+		}
+	}
+}
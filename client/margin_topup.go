@@ -0,0 +1,219 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+const (
+	defaultMarginTopUpInterval = time.Second * 30
+	marginTopUpWindow          = time.Hour
+)
+
+// MarginTopUpConfig defines the band in which the margin ratio, allocated margin / position value,
+// of every isolated position is kept. Positions below MinMarginRatio or above MaxMarginRatio are brought back to TargetMarginRatio.
+type MarginTopUpConfig struct {
+	MinMarginRatio    float64
+	TargetMarginRatio float64
+	// MaxMarginRatio is the ratio above which excess margin is removed. 0 never removes margin.
+	MaxMarginRatio float64
+	// MaxUSDCPerHour caps the USDC (6 decimals) moved in & out of isolated margin over the last hour. 0 means no cap.
+	MaxUSDCPerHour int64
+	// Interval between checks. Defaults to 30 seconds.
+	Interval time.Duration
+	// DryRun signs the margin updates with TransactOpts.DryRun, so they're only logged
+	DryRun bool
+	// Logf is called for every margin update, including dry runs
+	Logf func(format string, args ...any)
+	// OnError is called from the service goroutine whenever a check or a margin update fails
+	OnError func(err error)
+}
+
+type marginMove struct {
+	time   time.Time
+	amount int64
+}
+
+// MarginTopUp keeps the margin ratio of isolated positions within the configured band
+type MarginTopUp struct {
+	txClient *TxClient
+	config   MarginTopUpConfig
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu    sync.Mutex
+	moves []marginMove
+}
+
+func (c *TxClient) StartMarginTopUp(config MarginTopUpConfig) (*MarginTopUp, error) {
+	if config.MinMarginRatio <= 0 || config.TargetMarginRatio < config.MinMarginRatio {
+		return nil, fmt.Errorf("margin ratios should satisfy 0 < MinMarginRatio <= TargetMarginRatio")
+	}
+	if config.MaxMarginRatio != 0 && config.MaxMarginRatio < config.TargetMarginRatio {
+		return nil, fmt.Errorf("MaxMarginRatio should not be less than TargetMarginRatio")
+	}
+	if config.Interval < 0 {
+		return nil, fmt.Errorf("margin top up interval should not be negative")
+	}
+	if config.Interval == 0 {
+		config.Interval = defaultMarginTopUpInterval
+	}
+	if c.apiClient == nil {
+		return nil, fmt.Errorf("HTTPClient is nil, can't watch positions")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &MarginTopUp{
+		txClient: c,
+		config:   config,
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go m.run()
+	return m, nil
+}
+
+func (m *MarginTopUp) run() {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+	for {
+		if err := m.check(); err != nil && m.config.OnError != nil {
+			m.config.OnError(err)
+		}
+
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *MarginTopUp) check() error {
+	account, err := m.txClient.apiClient.GetAccount(m.txClient.accountIndex)
+	if err != nil {
+		return err
+	}
+
+	for _, position := range account.Positions {
+		if position.MarginMode != txtypes.IsolatedMargin {
+			continue
+		}
+		value, err := parseDecimal(position.PositionValue)
+		if err != nil {
+			return err
+		}
+		allocated, err := parseDecimal(position.AllocatedMargin)
+		if err != nil {
+			return err
+		}
+		value = math.Abs(value)
+		if value == 0 {
+			continue
+		}
+
+		ratio := allocated / value
+		if ratio >= m.config.MinMarginRatio && (m.config.MaxMarginRatio == 0 || ratio <= m.config.MaxMarginRatio) {
+			continue
+		}
+
+		delta := toTicks(m.config.TargetMarginRatio*value-allocated, usdcDecimals)
+		if err := m.move(position.MarketIndex, ratio, delta); err != nil {
+			return fmt.Errorf("failed to update margin of market %v. err: %w", position.MarketIndex, err)
+		}
+	}
+	return nil
+}
+
+// move adds delta USDC to the isolated margin of the market, removing it if negative, within the hourly budget
+func (m *MarginTopUp) move(marketIndex uint8, ratio float64, delta int64) error {
+	amount := delta
+	if amount < 0 {
+		amount = -amount
+	}
+	if m.config.MaxUSDCPerHour > 0 {
+		budget := m.config.MaxUSDCPerHour - m.MovedLastHour()
+		if budget <= 0 {
+			m.logf("market %v: margin ratio %.4f out of band but the hourly budget is used up", marketIndex, ratio)
+			return nil
+		}
+		amount = min(amount, budget)
+	}
+	if amount == 0 {
+		return nil
+	}
+	if delta < 0 {
+		delta = -amount
+	} else {
+		delta = amount
+	}
+
+	m.logf("market %v: margin ratio %.4f, moving %v USDC (dry run: %v)", marketIndex, ratio, toDecimal(delta, usdcDecimals), m.config.DryRun)
+	txInfo, err := m.txClient.getIsolatedMarginTransaction(marketIndex, delta, &types.TransactOpts{DryRun: m.config.DryRun})
+	if err != nil {
+		return err
+	}
+	sent, err := m.txClient.SendTx(txInfo)
+	if sent != nil && !sent.DryRun && sent.TxHash != "" {
+		// charged as soon as Lighter accepts the tx, it may still execute if waiting for it fails below
+		m.mu.Lock()
+		m.moves = append(m.moves, marginMove{time: time.Now(), amount: amount})
+		m.mu.Unlock()
+	}
+	if err != nil {
+		return err
+	}
+	if sent.DryRun {
+		return nil
+	}
+
+	result, err := m.txClient.tracker.Wait(m.ctx, sent.TxHash, txInfo.ExpiredAt)
+	if err != nil {
+		return err
+	}
+	if result.State != TxStateExecuted {
+		return fmt.Errorf("margin update tx %s was %v", result.TxHash, result.State)
+	}
+	return nil
+}
+
+// MovedLastHour returns the USDC (6 decimals) moved in & out of isolated margin over the last hour
+func (m *MarginTopUp) MovedLastHour() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cutoff := time.Now().Add(-marginTopUpWindow)
+	kept := m.moves[:0]
+	total := int64(0)
+	for _, move := range m.moves {
+		if move.time.After(cutoff) {
+			kept = append(kept, move)
+			total += move.amount
+		}
+	}
+	m.moves = kept
+	return total
+}
+
+// Stop ends the service, interrupting any margin update still waiting for its tx to execute
+func (m *MarginTopUp) Stop() {
+	m.cancel()
+	<-m.done
+}
+
+func (m *MarginTopUp) logf(format string, args ...any) {
+	if m.config.Logf != nil {
+		m.config.Logf(format, args...)
+	}
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMarginMoveIsChargedOnceSent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/nextNonce":
+			w.Write([]byte(`{"code":200,"nonce":1}`))
+		case "/api/v1/sendTx":
+			w.Write([]byte(`{"code":200,"tx_hash":"0xaa"}`))
+		default:
+			// the tx is never indexed, so waiting for it only ends with the context
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	txClient := newTestTxClient(t, NewHTTPClient(server.URL))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	m := &MarginTopUp{
+		txClient: txClient,
+		config:   MarginTopUpConfig{MaxUSDCPerHour: 1_000_000},
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	if err := m.move(0, 0.01, 400_000); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if moved := m.MovedLastHour(); moved != 400_000 {
+		t.Fatalf("expected the sent move to be charged, got %v", moved)
+	}
+}
+
+func TestStartMarginTopUpRejectsInvalidConfig(t *testing.T) {
+	txClient := newTestTxClient(t, NewHTTPClient("http://127.0.0.1:1"))
+	configs := []MarginTopUpConfig{
+		{MinMarginRatio: 0, TargetMarginRatio: 0.2},
+		{MinMarginRatio: 0.2, TargetMarginRatio: 0.1},
+		{MinMarginRatio: 0.1, TargetMarginRatio: 0.2, MaxMarginRatio: 0.15},
+		// would make time.NewTicker panic inside the service goroutine
+		{MinMarginRatio: 0.1, TargetMarginRatio: 0.2, Interval: -time.Second},
+	}
+	for _, config := range configs {
+		if m, err := txClient.StartMarginTopUp(config); err == nil {
+			m.Stop()
+			t.Fatalf("expected %+v to be rejected", config)
+		}
+	}
+}
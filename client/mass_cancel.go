@@ -0,0 +1,152 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+const (
+	maxTxBatchSize = 50
+)
+
+type CancelResult struct {
+	MarketIndex      uint8
+	OrderIndex       int64
+	ClientOrderIndex int64
+	TxHash           string
+	// Err is set if the cancel could not be signed or its batch was rejected
+	Err error
+}
+
+type MassCancelResult struct {
+	Results []*CancelResult
+}
+
+func (r *MassCancelResult) Succeeded() []*CancelResult {
+	ret := make([]*CancelResult, 0, len(r.Results))
+	for _, result := range r.Results {
+		if result.Err == nil {
+			ret = append(ret, result)
+		}
+	}
+	return ret
+}
+
+func (r *MassCancelResult) Failed() []*CancelResult {
+	ret := make([]*CancelResult, 0)
+	for _, result := range r.Results {
+		if result.Err != nil {
+			ret = append(ret, result)
+		}
+	}
+	return ret
+}
+
+// CancelOrders cancels the active orders of the market for which shouldCancel returns true, or all of them if shouldCancel is nil.
+// The cancels are signed with consecutive nonces and submitted in batches.
+func (c *TxClient) CancelOrders(marketIndex uint8, shouldCancel func(order *Order) bool, ops *types.TransactOpts) (*MassCancelResult, error) {
+	if c.apiClient == nil {
+		return nil, fmt.Errorf("HTTPClient is nil, can't get active orders")
+	}
+	auth, err := c.authToken()
+	if err != nil {
+		return nil, err
+	}
+	orders, err := c.apiClient.GetActiveOrders(c.accountIndex, marketIndex, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]*CancelResult, 0, len(orders))
+	for _, order := range orders {
+		if shouldCancel != nil && !shouldCancel(order) {
+			continue
+		}
+		targets = append(targets, &CancelResult{
+			MarketIndex:      marketIndex,
+			OrderIndex:       order.OrderIndex,
+			ClientOrderIndex: order.ClientOrderIndex,
+		})
+	}
+	return c.cancelInBatches(targets, ops)
+}
+
+// CancelMarketOrders cancels every active order of the market
+func (c *TxClient) CancelMarketOrders(marketIndex uint8, ops *types.TransactOpts) (*MassCancelResult, error) {
+	return c.CancelOrders(marketIndex, nil, ops)
+}
+
+// CancelSideOrders cancels the active asks or bids of the market
+func (c *TxClient) CancelSideOrders(marketIndex uint8, isAsk bool, ops *types.TransactOpts) (*MassCancelResult, error) {
+	return c.CancelOrders(marketIndex, func(order *Order) bool {
+		return order.IsAsk == isAsk
+	}, ops)
+}
+
+func (c *TxClient) cancelInBatches(targets []*CancelResult, ops *types.TransactOpts) (*MassCancelResult, error) {
+	result := &MassCancelResult{Results: targets}
+	if len(targets) == 0 {
+		return result, nil
+	}
+
+	ops, err := c.FullFillDefaultOps(ops)
+	if err != nil {
+		return nil, err
+	}
+	nonce := *ops.Nonce
+
+	for start := 0; start < len(targets); start += maxTxBatchSize {
+		end := min(start+maxTxBatchSize, len(targets))
+		batch := targets[start:end]
+
+		txs := make([]txtypes.TxInfo, 0, len(batch))
+		for _, target := range batch {
+			// orders without a client order index can only be canceled by their order index
+			index := target.OrderIndex
+			if target.ClientOrderIndex != txtypes.NilClientOrderIndex {
+				index = target.ClientOrderIndex
+			}
+
+			txOps := *ops
+			txNonce := nonce
+			txOps.Nonce = &txNonce
+			tx, err := c.GetCancelOrderTransaction(&types.CancelOrderTxReq{
+				MarketIndex: target.MarketIndex,
+				Index:       index,
+			}, &txOps)
+			if err != nil {
+				// signing only fails on invalid input, so the nonce is reused by the next cancel
+				target.Err = err
+				continue
+			}
+			nonce++
+			txs = append(txs, tx)
+		}
+		if len(txs) == 0 {
+			continue
+		}
+
+		sent, err := c.SendTxBatch(txs)
+		if err != nil {
+			// the nonces of the following batches would no longer be consecutive
+			for _, target := range targets[start:] {
+				if target.Err == nil {
+					target.Err = fmt.Errorf("cancel batch was rejected. err: %w", err)
+				}
+			}
+			return result, nil
+		}
+
+		i := 0
+		for _, target := range batch {
+			if target.Err != nil {
+				continue
+			}
+			target.TxHash = sent[i].TxHash
+			i++
+		}
+	}
+	return result, nil
+}
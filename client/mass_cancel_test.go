@@ -0,0 +1,102 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+func TestCancelOrdersInBatches(t *testing.T) {
+	// 60 bids, the second of which can't be canceled as its index is invalid, and an ask
+	orders := make([]*Order, 0, 61)
+	for i := int64(1); i <= 60; i++ {
+		orders = append(orders, &Order{OrderIndex: txtypes.MinOrderIndex + i, ClientOrderIndex: i})
+	}
+	orders[1].OrderIndex, orders[1].ClientOrderIndex = -1, txtypes.NilClientOrderIndex
+	orders = append(orders, &Order{OrderIndex: txtypes.MinOrderIndex + 61, ClientOrderIndex: 61, IsAsk: true})
+
+	var mu sync.Mutex
+	var batches [][]int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/accountActiveOrders":
+			json.NewEncoder(w).Encode(&Orders{ResultCode: ResultCode{Code: 200}, Orders: orders})
+		case "/api/v1/sendTxBatch":
+			txInfos := []string{}
+			if err := json.Unmarshal([]byte(r.FormValue("tx_infos")), &txInfos); err != nil {
+				t.Error(err)
+			}
+			nonces := make([]int64, 0, len(txInfos))
+			hashes := make([]string, 0, len(txInfos))
+			for _, txInfo := range txInfos {
+				tx := &struct{ Nonce int64 }{}
+				if err := json.Unmarshal([]byte(txInfo), tx); err != nil {
+					t.Error(err)
+				}
+				nonces = append(nonces, tx.Nonce)
+				hashes = append(hashes, fmt.Sprintf("%q", fmt.Sprintf("0x%x", tx.Nonce)))
+			}
+			mu.Lock()
+			batches = append(batches, nonces)
+			first := len(batches) == 1
+			mu.Unlock()
+			if !first {
+				w.Write([]byte(`{"code":21104,"message":"invalid nonce"}`))
+				return
+			}
+			fmt.Fprintf(w, `{"code":200,"tx_hash":[%s]}`, strings.Join(hashes, ","))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	txClient := newTestTxClient(t, NewHTTPClient(server.URL))
+
+	nonce := int64(1)
+	result, err := txClient.CancelSideOrders(0, false, &types.TransactOpts{Nonce: &nonce})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(batches) != 2 || len(batches[0]) != 49 || len(batches[1]) != 10 {
+		t.Fatalf("expected batches of 49 & 10 cancels, got %v", batches)
+	}
+	// the nonce of the cancel which failed to sign is reused, so nonces stay consecutive across batches
+	expectedNonce := int64(1)
+	for _, batch := range batches {
+		for _, n := range batch {
+			if n != expectedNonce {
+				t.Fatalf("expected nonce %d, got %d in %v", expectedNonce, n, batches)
+			}
+			expectedNonce++
+		}
+	}
+
+	if len(result.Results) != 60 {
+		t.Fatalf("expected the 60 bids to be canceled, got %d results", len(result.Results))
+	}
+	succeeded, failed := result.Succeeded(), result.Failed()
+	if len(succeeded) != 49 || len(failed) != 11 {
+		t.Fatalf("expected 49 cancels to succeed & 11 to fail, got %d & %d", len(succeeded), len(failed))
+	}
+	for i, cancel := range succeeded {
+		if expected := fmt.Sprintf("0x%x", i+1); cancel.TxHash != expected {
+			t.Fatalf("expected tx hash %s, got %s", expected, cancel.TxHash)
+		}
+	}
+	if failed[0].OrderIndex != -1 {
+		t.Fatalf("expected the invalid order to fail first, got %+v", failed[0])
+	}
+	for _, cancel := range failed[1:] {
+		if cancel.TxHash != "" || !strings.Contains(cancel.Err.Error(), "cancel batch was rejected") {
+			t.Fatalf("expected the cancels of the second batch to be rejected, got %+v", cancel)
+		}
+	}
+}
@@ -0,0 +1,570 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+type OrderState uint8
+
+const (
+	OrderStateSent OrderState = iota
+	OrderStateOpen
+	OrderStatePartiallyFilled
+	OrderStateFilled
+	OrderStateCanceled
+	OrderStateRejected
+	// OrderStateDryRun is the state of orders placed with TransactOpts.DryRun, which never reach Lighter
+	OrderStateDryRun
+)
+
+func (s OrderState) String() string {
+	switch s {
+	case OrderStateSent:
+		return "sent"
+	case OrderStateOpen:
+		return "open"
+	case OrderStatePartiallyFilled:
+		return "partially filled"
+	case OrderStateFilled:
+		return "filled"
+	case OrderStateCanceled:
+		return "canceled"
+	case OrderStateRejected:
+		return "rejected"
+	case OrderStateDryRun:
+		return "dry run"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(s))
+	}
+}
+
+func (s OrderState) IsTerminal() bool {
+	return s == OrderStateFilled || s == OrderStateCanceled || s == OrderStateRejected || s == OrderStateDryRun
+}
+
+type ManagedOrder struct {
+	ID               string
+	MarketIndex      uint8
+	ClientOrderIndex int64
+	OrderIndex       int64
+	IsAsk            uint8
+	BaseAmount       int64
+	Price            uint32
+	TxHash           string
+	ExpiredAt        int64 // unix millis, of the CreateOrder tx
+	Tags             []string
+	State            OrderState
+	// FilledBaseAmount & RemainingBaseAmount are decimal strings, as reported by Lighter
+	FilledBaseAmount    string
+	RemainingBaseAmount string
+	UpdatedAt           int64 // unix millis
+}
+
+// orderManagerState is a line of the state file. The first line is a snapshot of every order, the next ones are
+// appended as orders change, each overriding the previous state of its orders.
+type orderManagerState struct {
+	NextClientOrderIndex int64                    `json:",omitempty"`
+	Orders               map[string]*ManagedOrder `json:",omitempty"`
+}
+
+// clientOrderIndexBlock is how many client order indexes are reserved by a single write to the state file
+const clientOrderIndexBlock = 1000
+
+// OrderManager allocates client order indexes, tracks the lifecycle of the orders it placed
+// and lets them be modified & canceled by caller chosen IDs. Its state is persisted to a file, so it survives restarts.
+type OrderManager struct {
+	txClient *TxClient
+	path     string
+
+	mu                   sync.Mutex
+	file                 *os.File
+	nextClientOrderIndex int64
+	// [reservedFrom, reservedUntil) are the client order indexes which can be handed out without writing the state file
+	reservedFrom       int64
+	reservedUntil      int64
+	orders             map[string]*ManagedOrder
+	byClientOrderIndex map[int64]*ManagedOrder
+}
+
+func NewOrderManager(txClient *TxClient, statePath string) (*OrderManager, error) {
+	m := &OrderManager{
+		txClient: txClient,
+		path:     statePath,
+		// without previous state, start from the current time so indexes don't collide with ones used before
+		nextClientOrderIndex: time.Now().UnixMilli(),
+		orders:               make(map[string]*ManagedOrder),
+		byClientOrderIndex:   make(map[int64]*ManagedOrder),
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	lines := bytes.Split(data, []byte{'\n'})
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		state := &orderManagerState{}
+		if err := json.Unmarshal(line, state); err != nil {
+			if i == len(lines)-1 {
+				// torn by a crash in the middle of a write, the snapshot written below drops it
+				break
+			}
+			return nil, fmt.Errorf("failed to parse order manager state. err: %w", err)
+		}
+		m.apply(state)
+	}
+	m.reservedFrom, m.reservedUntil = m.nextClientOrderIndex, m.nextClientOrderIndex
+
+	if err := m.snapshot(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *OrderManager) apply(state *orderManagerState) {
+	if state.NextClientOrderIndex != 0 {
+		m.nextClientOrderIndex = state.NextClientOrderIndex
+	}
+	for id, order := range state.Orders {
+		if previous, ok := m.orders[id]; ok {
+			delete(m.byClientOrderIndex, previous.ClientOrderIndex)
+		}
+		m.orders[id] = order
+		m.byClientOrderIndex[order.ClientOrderIndex] = order
+	}
+}
+
+// snapshot rewrites the state file with the current state, through a temp file so a crash never leaves a half written state behind,
+// and reopens it for appending
+func (m *OrderManager) snapshot() error {
+	data, err := json.Marshal(&orderManagerState{
+		NextClientOrderIndex: m.reservedUntil,
+		Orders:               m.orders,
+	})
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	tmpPath := m.path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, m.path); err != nil {
+		return err
+	}
+
+	if m.file != nil {
+		m.file.Close()
+	}
+	m.file, err = os.OpenFile(m.path, os.O_APPEND|os.O_WRONLY, 0600)
+	return err
+}
+
+// persist appends the state changes to the state file
+func (m *OrderManager) persist(state *orderManagerState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if _, err := m.file.Write(data); err != nil {
+		return err
+	}
+	return m.file.Sync()
+}
+
+func (m *OrderManager) persistOrders(orders ...*ManagedOrder) error {
+	state := &orderManagerState{Orders: make(map[string]*ManagedOrder, len(orders))}
+	for _, order := range orders {
+		state.Orders[order.ID] = order
+	}
+	return m.persist(state)
+}
+
+// Close closes the state file
+func (m *OrderManager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.file.Close()
+}
+
+// AllocateClientOrderIndex returns a client order index which isn't used by any tracked order.
+// Indexes are reserved in blocks persisted before being handed out, so an index is never handed out twice.
+func (m *OrderManager) AllocateClientOrderIndex() (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.allocate()
+}
+
+func (m *OrderManager) allocate() (int64, error) {
+	// at most len(byClientOrderIndex) indexes are taken, so one of the next len+1 ones is free
+	for i := 0; i <= len(m.byClientOrderIndex); i++ {
+		index := m.nextClientOrderIndex
+		if index < txtypes.MinClientOrderIndex || index > txtypes.MaxClientOrderIndex {
+			index = txtypes.MinClientOrderIndex
+		}
+		m.nextClientOrderIndex = index + 1
+
+		// terminal orders are tracked until pruned, so their indexes can't be reused before
+		if _, ok := m.byClientOrderIndex[index]; ok {
+			continue
+		}
+		if index < m.reservedFrom || index >= m.reservedUntil {
+			if err := m.persist(&orderManagerState{NextClientOrderIndex: index + clientOrderIndexBlock}); err != nil {
+				return 0, err
+			}
+			m.reservedFrom, m.reservedUntil = index, index+clientOrderIndexBlock
+		}
+		return index, nil
+	}
+	return 0, fmt.Errorf("no client order index available")
+}
+
+// PlaceOrder assigns a client order index to the order, signs and sends it, and starts tracking it under id.
+// Tags can be used to cancel groups of orders with CancelTagged.
+func (m *OrderManager) PlaceOrder(id string, order *types.CreateOrderTxReq, ops *types.TransactOpts, tags ...string) (*ManagedOrder, error) {
+	m.mu.Lock()
+	if _, ok := m.orders[id]; ok {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("order %s already exists", id)
+	}
+	clientOrderIndex, err := m.allocate()
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	managed := &ManagedOrder{
+		ID:               id,
+		MarketIndex:      order.MarketIndex,
+		ClientOrderIndex: clientOrderIndex,
+		IsAsk:            order.IsAsk,
+		BaseAmount:       order.BaseAmount,
+		Price:            order.Price,
+		Tags:             tags,
+		State:            OrderStateSent,
+		UpdatedAt:        time.Now().UnixMilli(),
+	}
+	m.orders[id] = managed
+	m.byClientOrderIndex[clientOrderIndex] = managed
+	// persisted before sending, so the order is still tracked if the process dies right after
+	err = m.persistOrders(managed)
+	m.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	req := *order
+	req.ClientOrderIndex = clientOrderIndex
+	var sent *SendTxResult
+	txInfo, err := m.txClient.GetCreateOrderTransaction(&req, ops)
+	if err == nil {
+		sent, err = m.txClient.SendTx(txInfo)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if txInfo != nil {
+		managed.ExpiredAt = txInfo.ExpiredAt
+	}
+	if sent != nil {
+		managed.TxHash = sent.TxHash
+	}
+	switch {
+	case sent != nil && sent.TxHash != "" && err != nil:
+		// Lighter accepted the tx but something went wrong after, e.g. ErrTxHashMismatch. The order stays in Sent until synced.
+	case err != nil:
+		managed.State = OrderStateRejected
+	case sent.DryRun:
+		managed.State = OrderStateDryRun
+	}
+	managed.UpdatedAt = time.Now().UnixMilli()
+	if pErr := m.persistOrders(managed); pErr != nil && err == nil {
+		err = pErr
+	}
+	return managed.copy(), err
+}
+
+// CancelOrder cancels the order tracked under id. Its state changes once Lighter reports the cancel.
+func (m *OrderManager) CancelOrder(id string, ops *types.TransactOpts) (*SendTxResult, error) {
+	order, err := m.activeOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	txInfo, err := m.txClient.GetCancelOrderTransaction(&types.CancelOrderTxReq{
+		MarketIndex: order.MarketIndex,
+		Index:       order.ClientOrderIndex,
+	}, ops)
+	if err != nil {
+		return nil, err
+	}
+	return m.txClient.SendTx(txInfo)
+}
+
+// ModifyOrder changes the amount & prices of the order tracked under id
+func (m *OrderManager) ModifyOrder(id string, baseAmount int64, price uint32, triggerPrice uint32, ops *types.TransactOpts) (*SendTxResult, error) {
+	order, err := m.activeOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	txInfo, err := m.txClient.GetModifyOrderTransaction(&types.ModifyOrderTxReq{
+		MarketIndex:  order.MarketIndex,
+		Index:        order.ClientOrderIndex,
+		BaseAmount:   baseAmount,
+		Price:        price,
+		TriggerPrice: triggerPrice,
+	}, ops)
+	if err != nil {
+		return nil, err
+	}
+	sent, err := m.txClient.SendTx(txInfo)
+	if err != nil {
+		return sent, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	order = m.orders[id]
+	order.BaseAmount = baseAmount
+	order.Price = price
+	order.UpdatedAt = time.Now().UnixMilli()
+	return sent, m.persistOrders(order)
+}
+
+// CancelTagged cancels every non terminal order having the tag
+func (m *OrderManager) CancelTagged(tag string, ops *types.TransactOpts) (*MassCancelResult, error) {
+	m.mu.Lock()
+	targets := make([]*CancelResult, 0)
+	for _, order := range m.orders {
+		if order.State.IsTerminal() || !order.hasTag(tag) {
+			continue
+		}
+		targets = append(targets, &CancelResult{
+			MarketIndex:      order.MarketIndex,
+			OrderIndex:       order.OrderIndex,
+			ClientOrderIndex: order.ClientOrderIndex,
+		})
+	}
+	m.mu.Unlock()
+
+	return m.txClient.cancelInBatches(targets, ops)
+}
+
+func (m *OrderManager) activeOrder(id string) (*ManagedOrder, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	order, ok := m.orders[id]
+	if !ok {
+		return nil, fmt.Errorf("order %s not found", id)
+	}
+	if order.State.IsTerminal() {
+		return nil, fmt.Errorf("order %s is already %v", id, order.State)
+	}
+	return order.copy(), nil
+}
+
+func (m *OrderManager) Order(id string) (*ManagedOrder, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	order, ok := m.orders[id]
+	if !ok {
+		return nil, false
+	}
+	return order.copy(), true
+}
+
+func (m *OrderManager) Orders() []*ManagedOrder {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ret := make([]*ManagedOrder, 0, len(m.orders))
+	for _, order := range m.orders {
+		ret = append(ret, order.copy())
+	}
+	return ret
+}
+
+// ApplyOrderUpdate updates the tracked order matching the update's client order index.
+// It can be fed with order updates from the account websocket stream; Sync polls for them instead.
+// Returns false if the order isn't tracked by this manager.
+func (m *OrderManager) ApplyOrderUpdate(update *Order) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	order := m.applyOrderUpdate(update)
+	if order == nil {
+		return false, nil
+	}
+	return true, m.persistOrders(order)
+}
+
+// applyOrderUpdate returns the updated order, nil if it isn't tracked
+func (m *OrderManager) applyOrderUpdate(update *Order) *ManagedOrder {
+	order, ok := m.byClientOrderIndex[update.ClientOrderIndex]
+	if !ok || order.MarketIndex != update.MarketIndex {
+		return nil
+	}
+
+	order.OrderIndex = update.OrderIndex
+	order.FilledBaseAmount = update.FilledBaseAmount
+	order.RemainingBaseAmount = update.RemainingBaseAmount
+	order.State = orderStateFromStatus(update)
+	order.UpdatedAt = time.Now().UnixMilli()
+	return order
+}
+
+func orderStateFromStatus(order *Order) OrderState {
+	filled, _ := parseDecimal(order.FilledBaseAmount)
+	switch {
+	case order.Status == "filled":
+		return OrderStateFilled
+	// canceled, canceled-post-only, canceled-reduce-only, canceled-expired...
+	case strings.HasPrefix(order.Status, "canceled"):
+		return OrderStateCanceled
+	case order.Status == "open" && filled > 0:
+		return OrderStatePartiallyFilled
+	case order.Status == "open":
+		return OrderStateOpen
+	default:
+		// pending & in-progress orders haven't reached the book yet
+		return OrderStateSent
+	}
+}
+
+// Sync polls the active & recently inactive orders of every market with a non terminal order and applies them.
+// Sent orders which are in neither list are rejected once their CreateOrder tx failed or expired.
+func (m *OrderManager) Sync() error {
+	m.mu.Lock()
+	markets := make(map[uint8]bool)
+	for _, order := range m.orders {
+		if !order.State.IsTerminal() {
+			markets[order.MarketIndex] = true
+		}
+	}
+	m.mu.Unlock()
+	if len(markets) == 0 {
+		return nil
+	}
+
+	auth, err := m.txClient.authToken()
+	if err != nil {
+		return err
+	}
+	apiClient := m.txClient.HTTP()
+	if apiClient == nil {
+		return fmt.Errorf("HTTPClient is nil, can't sync orders")
+	}
+
+	var updates []*Order
+	for marketIndex := range markets {
+		// inactive first, so active state wins for orders which appear in both lists while being updated
+		inactive, err := apiClient.GetInactiveOrders(m.txClient.GetAccountIndex(), marketIndex, inactiveOrdersLookupLimit, auth)
+		if err != nil {
+			return err
+		}
+		active, err := apiClient.GetActiveOrders(m.txClient.GetAccountIndex(), marketIndex, auth)
+		if err != nil {
+			return err
+		}
+		updates = append(updates, inactive...)
+		updates = append(updates, active...)
+	}
+
+	m.mu.Lock()
+	var changed []*ManagedOrder
+	seen := make(map[*ManagedOrder]bool, len(updates))
+	for _, update := range updates {
+		if order := m.applyOrderUpdate(update); order != nil {
+			changed = append(changed, order)
+			seen[order] = true
+		}
+	}
+	var unseen []*ManagedOrder
+	for _, order := range m.orders {
+		if order.State == OrderStateSent && order.TxHash != "" && !seen[order] {
+			unseen = append(unseen, order.copy())
+		}
+	}
+	m.mu.Unlock()
+
+	// the tx of an order which never reached the book may have failed or expired, in which case the order never will
+	tracker := m.txClient.tracker
+	if tracker == nil {
+		tracker = NewTxTracker(apiClient, 0)
+	}
+	var rejected []string
+	for _, order := range unseen {
+		result, err := tracker.Poll(order.TxHash, order.ExpiredAt)
+		if err != nil {
+			return err
+		}
+		if result != nil && (result.State == TxStateFailed || result.State == TxStateExpired) {
+			rejected = append(rejected, order.ID)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range rejected {
+		// unless it got updated in the meantime
+		if order, ok := m.orders[id]; ok && order.State == OrderStateSent {
+			order.State = OrderStateRejected
+			order.UpdatedAt = time.Now().UnixMilli()
+			changed = append(changed, order)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return m.persistOrders(changed...)
+}
+
+// Prune stops tracking terminal orders, freeing their IDs & client order indexes, and compacts the state file
+func (m *OrderManager) Prune() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, order := range m.orders {
+		if order.State.IsTerminal() {
+			delete(m.orders, id)
+			delete(m.byClientOrderIndex, order.ClientOrderIndex)
+		}
+	}
+	return m.snapshot()
+}
+
+func (o *ManagedOrder) hasTag(tag string) bool {
+	for _, t := range o.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *ManagedOrder) copy() *ManagedOrder {
+	ret := *o
+	ret.Tags = append([]string(nil), o.Tags...)
+	return &ret
+}
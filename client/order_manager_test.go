@@ -0,0 +1,164 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+func TestAllocateSkipsTrackedClientOrderIndexes(t *testing.T) {
+	m, err := NewOrderManager(nil, filepath.Join(t.TempDir(), "orders"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	first, err := m.AllocateClientOrderIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// an order still tracked after the indexes wrapped around
+	tracked := &ManagedOrder{ID: "tracked", ClientOrderIndex: first + 1, State: OrderStateFilled}
+	m.orders[tracked.ID] = tracked
+	m.byClientOrderIndex[tracked.ClientOrderIndex] = tracked
+
+	second, err := m.AllocateClientOrderIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second != first+2 {
+		t.Fatalf("expected %d, got %d", first+2, second)
+	}
+}
+
+func TestOrderManagerReloadsAppendedState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders")
+	m, err := NewOrderManager(nil, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last int64
+	for i := 0; i < 3; i++ {
+		if last, err = m.AllocateClientOrderIndex(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	order := &ManagedOrder{ID: "a", ClientOrderIndex: last, State: OrderStateOpen}
+	m.orders[order.ID] = order
+	m.byClientOrderIndex[order.ClientOrderIndex] = order
+	if err := m.persistOrders(order); err != nil {
+		t.Fatal(err)
+	}
+	order.State = OrderStateFilled
+	if err := m.persistOrders(order); err != nil {
+		t.Fatal(err)
+	}
+	m.Close()
+
+	// a crash in the middle of a write leaves a torn last line
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(`{"Orders":{"a":{"ID":"a","Sta`); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	m, err = NewOrderManager(nil, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	reloaded, ok := m.Order("a")
+	if !ok || reloaded.State != OrderStateFilled {
+		t.Fatalf("expected the filled order to be reloaded, got %+v", reloaded)
+	}
+	next, err := m.AllocateClientOrderIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next <= last {
+		t.Fatalf("expected an index after %d, got %d", last, next)
+	}
+}
+
+func TestSyncRejectsOrdersWhoseTxFailedOrExpired(t *testing.T) {
+	var status atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/sendTx":
+			w.Write([]byte(`{"code":200,"tx_hash":"0xaa"}`))
+		case "/api/v1/tx":
+			fmt.Fprintf(w, `{"code":200,"hash":"0xaa","status":%v}`, status.Load())
+		case "/api/v1/accountActiveOrders", "/api/v1/accountInactiveOrders":
+			w.Write([]byte(`{"code":200,"orders":[]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	txClient := newTestTxClient(t, NewHTTPClient(server.URL))
+
+	m, err := NewOrderManager(txClient, filepath.Join(t.TempDir(), "orders"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	place := func(id string, expiredAt time.Time) {
+		t.Helper()
+		nonce := int64(1)
+		_, err := m.PlaceOrder(id, &types.CreateOrderTxReq{
+			BaseAmount: 100, Price: 1000, Type: txtypes.LimitOrder, TimeInForce: txtypes.GoodTillTime,
+			OrderExpiry: time.Now().Add(time.Hour).UnixMilli(),
+		}, &types.TransactOpts{Nonce: &nonce, ExpiredAt: expiredAt.UnixMilli()})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectState := func(id string, state OrderState) {
+		t.Helper()
+		order, ok := m.Order(id)
+		if !ok || order.State != state {
+			t.Fatalf("expected %s to be %v, got %+v", id, state, order)
+		}
+	}
+
+	// still pending before its expiry, the order may yet reach the book
+	status.Store(TxStatusPending)
+	place("pending", time.Now().Add(time.Hour))
+	if err := m.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	expectState("pending", OrderStateSent)
+
+	status.Store(TxStatusFailed)
+	if err := m.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	expectState("pending", OrderStateRejected)
+
+	// never executed past its expiry
+	status.Store(TxStatusPending)
+	place("expired", time.Now().Add(-time.Minute))
+	if err := m.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	expectState("expired", OrderStateRejected)
+
+	if err := m.Prune(); err != nil {
+		t.Fatal(err)
+	}
+	if orders := m.Orders(); len(orders) != 0 {
+		t.Fatalf("expected the rejected orders to be pruned, got %+v", orders)
+	}
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+// RedeemAll is the basis points of Redeem burning every held share
+const RedeemAll = 10_000
+
+type RedeemResult struct {
+	*PoolResult
+	// RealizedUSDC is the USDC (6 decimals) value of the burned shares at the pool share price read once the burn executed,
+	// which a burn doesn't move. Unlike the change of the account collateral, it isn't affected by other activity of the account.
+	// Not set for dry runs.
+	RealizedUSDC int64
+}
+
+// Invest mints the shares worth amount USDC (6 decimals) at the current share price.
+// Investing in a new pool fails with ErrTooManyInvestedPools once the account holds shares in MaxInvestedPublicPoolCount pools.
+func (p *PublicPool) Invest(ctx context.Context, amount int64, ops *types.TransactOpts) (*PoolResult, error) {
+	if amount <= 0 || amount > txtypes.MaxPoolEntryUSDC {
+		return nil, fmt.Errorf("amount should be larger than 0 and not larger than %v", int64(txtypes.MaxPoolEntryUSDC))
+	}
+	state, err := p.State()
+	if err != nil {
+		return nil, err
+	}
+	if state.HeldShares == 0 && state.InvestedPoolCount >= txtypes.MaxInvestedPublicPoolCount {
+		return nil, ErrTooManyInvestedPools
+	}
+	return p.mint(ctx, state, state.USDCToShares(amount, false), ops)
+}
+
+// Redeem burns basisPoints (larger than 0, up to RedeemAll) of the held shares, rounded down,
+// and reports the USDC realized once the burn executed
+func (p *PublicPool) Redeem(ctx context.Context, basisPoints int64, ops *types.TransactOpts) (*RedeemResult, error) {
+	if basisPoints <= 0 || basisPoints > RedeemAll {
+		return nil, fmt.Errorf("basis points should be larger than 0 and not larger than %v", RedeemAll)
+	}
+	state, err := p.State()
+	if err != nil {
+		return nil, err
+	}
+	shares := mulDiv(state.HeldShares, basisPoints, RedeemAll, false)
+
+	poolResult, err := p.burn(ctx, state, shares, ops)
+	if err != nil {
+		return nil, err
+	}
+	result := &RedeemResult{PoolResult: poolResult}
+	if poolResult.State == TxStateDryRun {
+		return result, nil
+	}
+
+	after, err := p.State()
+	if err != nil {
+		return result, fmt.Errorf("burn executed but failed to get realized value. err: %w", err)
+	}
+	result.RealizedUSDC = after.SharesToUSDC(shares)
+	return result, nil
+}
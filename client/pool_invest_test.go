@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+// poolServer serves pool 100, whose shares are worth 2000 (0.002 USDC) until a burn executes and 2100 after,
+// and account 7 holding heldShares of it along with shares of otherPools other pools
+type poolServer struct {
+	mu         sync.Mutex
+	heldShares int64
+	otherPools int
+	burned     bool
+	// sent are the tx types & share amounts of the sent txs
+	sent []string
+}
+
+func newPoolServer(t *testing.T, heldShares int64, otherPools int) (*poolServer, *TxClient) {
+	t.Helper()
+	s := &poolServer{heldShares: heldShares, otherPools: otherPools}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch {
+		case r.URL.Path == "/api/v1/account" && r.URL.Query().Get("value") == "100":
+			totalShares, totalAssetValue := int64(1_000_000), "2000"
+			if s.burned {
+				totalShares, totalAssetValue = 999_667, "2099.3007"
+			}
+			fmt.Fprintf(w, `{"code":200,"accounts":[{"index":100,"total_asset_value":%q,"pool_info":{"operator_fee":"10","min_operator_share_rate":"0","total_shares":%d,"operator_shares":500000,"operator_account_index":8}}]}`,
+				totalAssetValue, totalShares)
+		case r.URL.Path == "/api/v1/account" && r.URL.Query().Get("value") == "7":
+			shares := make([]string, 0, s.otherPools+1)
+			for i := 0; i < s.otherPools; i++ {
+				shares = append(shares, fmt.Sprintf(`{"public_pool_index":%d,"shares_amount":10,"entry_usdc":"1"}`, 200+i))
+			}
+			shares = append(shares, fmt.Sprintf(`{"public_pool_index":100,"shares_amount":%d,"entry_usdc":"1"}`, s.heldShares))
+			fmt.Fprintf(w, `{"code":200,"accounts":[{"index":7,"shares":[%s]}]}`, strings.Join(shares, ","))
+		case r.URL.Path == "/api/v1/nextNonce":
+			w.Write([]byte(`{"code":200,"nonce":1}`))
+		case r.URL.Path == "/api/v1/sendTx":
+			tx := &struct{ ShareAmount int64 }{}
+			if err := json.Unmarshal([]byte(r.FormValue("tx_info")), tx); err != nil {
+				t.Error(err)
+			}
+			txType, _ := strconv.Atoi(r.FormValue("tx_type"))
+			s.sent = append(s.sent, fmt.Sprintf("%d:%d", txType, tx.ShareAmount))
+			if txType == txtypes.TxTypeL2BurnShares {
+				s.burned = true
+			}
+			w.Write([]byte(`{"code":200,"tx_hash":"0xaa"}`))
+		case r.URL.Path == "/api/v1/tx":
+			fmt.Fprintf(w, `{"code":200,"hash":"0xaa","status":%v}`, TxStatusExecuted)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	apiClient := NewHTTPClient(server.URL)
+	txClient := newTestTxClient(t, apiClient)
+	txClient.SetTxTracker(NewTxTracker(apiClient, time.Millisecond))
+	return s, txClient
+}
+
+func (s *poolServer) sentTxs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.sent...)
+}
+
+func TestInvest(t *testing.T) {
+	s, txClient := newPoolServer(t, 0, 3)
+	pool := txClient.PublicPool(100)
+
+	// 10 USDC buys 5000 shares at 0.002 USDC
+	result, err := pool.Invest(context.Background(), 10_000_000, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Shares != 5000 || result.USDC != 10_000_000 || result.State != TxStateExecuted {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	// Deposit goes through the same checks
+	if _, err := pool.Deposit(context.Background(), 2_000, nil); err != nil {
+		t.Fatal(err)
+	}
+	if sent := s.sentTxs(); len(sent) != 2 || sent[0] != "18:5000" || sent[1] != "18:1" {
+		t.Fatalf("unexpected txs %v", sent)
+	}
+
+	for _, amount := range []int64{0, -1, txtypes.MaxPoolEntryUSDC + 1} {
+		if _, err := pool.Invest(context.Background(), amount, nil); err == nil {
+			t.Fatalf("expected investing %v to fail", amount)
+		}
+		if _, err := pool.Deposit(context.Background(), amount, nil); err == nil {
+			t.Fatalf("expected depositing %v to fail", amount)
+		}
+	}
+	if sent := s.sentTxs(); len(sent) != 2 {
+		t.Fatalf("expected no tx to be sent, got %v", sent)
+	}
+}
+
+func TestInvestInTooManyPools(t *testing.T) {
+	s, txClient := newPoolServer(t, 0, int(txtypes.MaxInvestedPublicPoolCount))
+	pool := txClient.PublicPool(100)
+
+	if _, err := pool.Invest(context.Background(), 10_000_000, nil); !errors.Is(err, ErrTooManyInvestedPools) {
+		t.Fatalf("expected ErrTooManyInvestedPools, got %v", err)
+	}
+	if _, err := pool.Deposit(context.Background(), 10_000_000, nil); !errors.Is(err, ErrTooManyInvestedPools) {
+		t.Fatalf("expected ErrTooManyInvestedPools from Deposit, got %v", err)
+	}
+
+	// adding to a pool the account already invests in doesn't take another slot
+	s.mu.Lock()
+	s.heldShares = 100
+	s.mu.Unlock()
+	if _, err := pool.Invest(context.Background(), 10_000_000, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedeem(t *testing.T) {
+	tests := []struct {
+		name        string
+		basisPoints int64
+		shares      int64
+		err         bool
+	}{
+		// a third, rounded down
+		{name: "part", basisPoints: 3333, shares: 333},
+		{name: "all", basisPoints: RedeemAll, shares: 1000},
+		{name: "smallest part", basisPoints: 10, shares: 1},
+		{name: "zero", basisPoints: 0, err: true},
+		{name: "more than all", basisPoints: RedeemAll + 1, err: true},
+		// less than a share
+		{name: "too little", basisPoints: 1, err: true},
+	}
+	for _, tt := range tests {
+		s, txClient := newPoolServer(t, 1000, 0)
+		result, err := txClient.PublicPool(100).Redeem(context.Background(), tt.basisPoints, nil)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("%s: expected an error", tt.name)
+			}
+			if sent := s.sentTxs(); len(sent) != 0 {
+				t.Fatalf("%s: expected no tx to be sent, got %v", tt.name, sent)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if sent := s.sentTxs(); len(sent) != 1 || sent[0] != fmt.Sprintf("19:%d", tt.shares) {
+			t.Fatalf("%s: unexpected txs %v", tt.name, sent)
+		}
+		// USDC is estimated at the share value before the burn, RealizedUSDC at the one read after
+		if result.Shares != tt.shares || result.USDC != tt.shares*2000 || result.RealizedUSDC != tt.shares*2100 {
+			t.Fatalf("%s: unexpected result %+v", tt.name, result)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+// PoolState is a snapshot of a public pool, along with the position of the client's account in it
+type PoolState struct {
+	PoolIndex int64
+	Status    uint8
+	// OperatorFee is in FeeTick units
+	OperatorFee int64
+	// MinOperatorShareRate is in ShareTick units
+	MinOperatorShareRate int64
+	TotalShares          int64
+	OperatorShares       int64
+	// TotalAssetValue is the USDC (6 decimals) value of the pool
+	TotalAssetValue int64
+
+	// IsOperator is set when the client's account operates the pool
+	IsOperator bool
+	// HeldShares are the shares of the pool held by the client's account
+	HeldShares int64
+	// EntryUSDC is the USDC (6 decimals) the client's account invested in the pool
+	EntryUSDC int64
+	// InvestedPoolCount is how many pools the client's account holds shares in, including this one
+	InvestedPoolCount int64
+}
+
+// ShareValue returns the USDC (6 decimals) value of a share. Pools without shares are valued at InitialPoolShareValue.
+func (s *PoolState) ShareValue() float64 {
+	if s.TotalShares == 0 {
+		return float64(txtypes.InitialPoolShareValue)
+	}
+	return float64(s.TotalAssetValue) / float64(s.TotalShares)
+}
+
+// SharesToUSDC returns the USDC (6 decimals) value of the shares, rounded down
+func (s *PoolState) SharesToUSDC(shares int64) int64 {
+	if s.TotalShares == 0 {
+		return shares * txtypes.InitialPoolShareValue
+	}
+	return mulDiv(shares, s.TotalAssetValue, s.TotalShares, false)
+}
+
+// USDCToShares returns how many shares are worth amount USDC (6 decimals), rounded down, or up with roundUp
+func (s *PoolState) USDCToShares(amount int64, roundUp bool) int64 {
+	if s.TotalShares == 0 || s.TotalAssetValue == 0 {
+		return mulDiv(amount, 1, txtypes.InitialPoolShareValue, roundUp)
+	}
+	return mulDiv(amount, s.TotalShares, s.TotalAssetValue, roundUp)
+}
+
+// OperatorShareRate returns the share of the pool held by the operator, in ShareTick units
+func (s *PoolState) OperatorShareRate() int64 {
+	if s.TotalShares == 0 {
+		return txtypes.ShareTick
+	}
+	return mulDiv(s.OperatorShares, txtypes.ShareTick, s.TotalShares, false)
+}
+
+// checkOperatorShareRate returns ErrOperatorShareRateTooLow if minting (positive) or burning (negative) shares
+// would take the operator share rate below MinOperatorShareRate
+func (s *PoolState) checkOperatorShareRate(shares int64) error {
+	operatorShares := s.OperatorShares
+	if s.IsOperator {
+		operatorShares += shares
+	}
+	totalShares := s.TotalShares + shares
+	if totalShares <= 0 {
+		return nil
+	}
+
+	// operatorShares / totalShares >= MinOperatorShareRate / ShareTick
+	lhs := new(big.Int).Mul(big.NewInt(operatorShares), big.NewInt(txtypes.ShareTick))
+	rhs := new(big.Int).Mul(big.NewInt(s.MinOperatorShareRate), big.NewInt(totalShares))
+	if lhs.Cmp(rhs) < 0 {
+		return ErrOperatorShareRateTooLow
+	}
+	return nil
+}
+
+type PoolResult struct {
+	*TxResult
+	Shares int64
+	// USDC is the estimated USDC (6 decimals) value of the shares, at the share value before the tx
+	USDC int64
+}
+
+// PublicPool operates on, or invests in, a public pool through the client's account
+type PublicPool struct {
+	txClient  *TxClient
+	poolIndex int64
+}
+
+func (c *TxClient) PublicPool(poolIndex int64) *PublicPool {
+	return &PublicPool{txClient: c, poolIndex: poolIndex}
+}
+
+// CreatePublicPool creates a public pool operated by the client's account, seeded with initialUSDC (6 decimals), once the tx is executed.
+// operatorFee is in FeeTick units, minOperatorShareRate in ShareTick units.
+func (c *TxClient) CreatePublicPool(ctx context.Context, operatorFee int64, initialUSDC int64, minOperatorShareRate int64, ops *types.TransactOpts) (*PublicPool, error) {
+	txInfo, err := c.GetCreatePublicPoolTransaction(&types.CreatePublicPoolTxReq{
+		OperatorFee:          operatorFee,
+		InitialTotalShares:   initialUSDC / txtypes.InitialPoolShareValue,
+		MinOperatorShareRate: minOperatorShareRate,
+	}, ops)
+	if err != nil {
+		return nil, err
+	}
+	poolIndex, err := c.SubAccounts().sendAndFindNew(ctx, txInfo)
+	if err != nil {
+		return nil, err
+	}
+	return c.PublicPool(poolIndex), nil
+}
+
+func (p *PublicPool) Index() int64 {
+	return p.poolIndex
+}
+
+// State queries the pool & the client's account
+func (p *PublicPool) State() (*PoolState, error) {
+	c := p.txClient
+	if c.apiClient == nil {
+		return nil, fmt.Errorf("HTTPClient is nil, can't get pool state")
+	}
+	pool, err := c.apiClient.GetAccount(p.poolIndex)
+	if err != nil {
+		return nil, err
+	}
+	if pool.PoolInfo == nil {
+		return nil, fmt.Errorf("account %v is not a public pool", p.poolIndex)
+	}
+	account, err := c.apiClient.GetAccount(c.accountIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	operatorFee, err := parseDecimal(pool.PoolInfo.OperatorFee)
+	if err != nil {
+		return nil, err
+	}
+	minOperatorShareRate, err := parseDecimal(pool.PoolInfo.MinOperatorShareRate)
+	if err != nil {
+		return nil, err
+	}
+	totalAssetValue, err := parseDecimal(pool.TotalAssetValue)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &PoolState{
+		PoolIndex:            p.poolIndex,
+		Status:               pool.PoolInfo.Status,
+		OperatorFee:          percentToTicks(operatorFee, txtypes.FeeTick),
+		MinOperatorShareRate: percentToTicks(minOperatorShareRate, txtypes.ShareTick),
+		TotalShares:          pool.PoolInfo.TotalShares,
+		OperatorShares:       pool.PoolInfo.OperatorShares,
+		TotalAssetValue:      toTicks(totalAssetValue, usdcDecimals),
+		IsOperator:           pool.PoolInfo.OperatorAccountIndex == c.accountIndex,
+	}
+	for _, share := range account.Shares {
+		if share.SharesAmount > 0 {
+			state.InvestedPoolCount++
+		}
+		if share.PublicPoolIndex != p.poolIndex {
+			continue
+		}
+		entryUSDC, err := parseDecimal(share.EntryUSDC)
+		if err != nil {
+			return nil, err
+		}
+		state.HeldShares = share.SharesAmount
+		state.EntryUSDC = toTicks(entryUSDC, usdcDecimals)
+	}
+	return state, nil
+}
+
+// Update changes the status, operator fee & min operator share rate of the pool. Only the operator can update it.
+func (p *PublicPool) Update(ctx context.Context, status uint8, operatorFee int64, minOperatorShareRate int64, ops *types.TransactOpts) (*TxResult, error) {
+	txInfo, err := p.txClient.GetUpdatePublicPoolTransaction(&types.UpdatePublicPoolTxReq{
+		PublicPoolIndex:      p.poolIndex,
+		Status:               status,
+		OperatorFee:          operatorFee,
+		MinOperatorShareRate: minOperatorShareRate,
+	}, ops)
+	if err != nil {
+		return nil, err
+	}
+	return p.sendAndWait(ctx, txInfo)
+}
+
+// Deposit mints the shares worth amount USDC (6 decimals) at the current share value, going through the checks of Invest
+func (p *PublicPool) Deposit(ctx context.Context, amount int64, ops *types.TransactOpts) (*PoolResult, error) {
+	return p.Invest(ctx, amount, ops)
+}
+
+// Withdraw burns the shares worth amount USDC (6 decimals) at the current share value, rounded up
+func (p *PublicPool) Withdraw(ctx context.Context, amount int64, ops *types.TransactOpts) (*PoolResult, error) {
+	state, err := p.State()
+	if err != nil {
+		return nil, err
+	}
+	return p.burn(ctx, state, state.USDCToShares(amount, true), ops)
+}
+
+func (p *PublicPool) Mint(ctx context.Context, shares int64, ops *types.TransactOpts) (*PoolResult, error) {
+	state, err := p.State()
+	if err != nil {
+		return nil, err
+	}
+	return p.mint(ctx, state, shares, ops)
+}
+
+// Burn burns the shares, first checking the operator share rate doesn't fall below MinOperatorShareRate
+func (p *PublicPool) Burn(ctx context.Context, shares int64, ops *types.TransactOpts) (*PoolResult, error) {
+	state, err := p.State()
+	if err != nil {
+		return nil, err
+	}
+	return p.burn(ctx, state, shares, ops)
+}
+
+func (p *PublicPool) mint(ctx context.Context, state *PoolState, shares int64, ops *types.TransactOpts) (*PoolResult, error) {
+	if err := checkShareAmount(shares); err != nil {
+		return nil, err
+	}
+	if err := state.checkOperatorShareRate(shares); err != nil {
+		return nil, err
+	}
+
+	txInfo, err := p.txClient.GetMintSharesTransaction(&types.MintSharesTxReq{
+		PublicPoolIndex: p.poolIndex,
+		ShareAmount:     shares,
+	}, ops)
+	if err != nil {
+		return nil, err
+	}
+	result, err := p.sendAndWait(ctx, txInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &PoolResult{TxResult: result, Shares: shares, USDC: state.SharesToUSDC(shares)}, nil
+}
+
+func (p *PublicPool) burn(ctx context.Context, state *PoolState, shares int64, ops *types.TransactOpts) (*PoolResult, error) {
+	if err := checkShareAmount(shares); err != nil {
+		return nil, err
+	}
+	if shares > state.HeldShares {
+		return nil, fmt.Errorf("can't burn %v shares, only %v are held", shares, state.HeldShares)
+	}
+	if err := state.checkOperatorShareRate(-shares); err != nil {
+		return nil, err
+	}
+
+	txInfo, err := p.txClient.GetBurnSharesTransaction(&types.BurnSharesTxReq{
+		PublicPoolIndex: p.poolIndex,
+		ShareAmount:     shares,
+	}, ops)
+	if err != nil {
+		return nil, err
+	}
+	result, err := p.sendAndWait(ctx, txInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &PoolResult{TxResult: result, Shares: shares, USDC: state.SharesToUSDC(shares)}, nil
+}
+
+func (p *PublicPool) sendAndWait(ctx context.Context, tx txtypes.TxInfo) (*TxResult, error) {
+	result, err := p.txClient.SendAndWait(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if result.State != TxStateExecuted && result.State != TxStateDryRun {
+		return result, fmt.Errorf("pool tx %s was %v", result.TxHash, result.State)
+	}
+	return result, nil
+}
+
+func checkShareAmount(shares int64) error {
+	if shares < txtypes.MinPoolSharesToMintOrBurn {
+		return fmt.Errorf("amount is worth less than %v share", txtypes.MinPoolSharesToMintOrBurn)
+	}
+	if shares > txtypes.MaxPoolSharesToMintOrBurn {
+		return fmt.Errorf("share amount %v is larger than %v", shares, txtypes.MaxPoolSharesToMintOrBurn)
+	}
+	return nil
+}
+
+// percentToTicks converts a percentage, as reported by the API, to tick units, rounding to the nearest tick
+// so e.g. "0.29" is 29 ShareTick units rather than 28
+func percentToTicks(percent float64, tick int64) int64 {
+	return int64(math.Round(percent / 100 * float64(tick)))
+}
+
+// mulDiv returns a * b / c without overflowing, rounded down, or up with roundUp
+func mulDiv(a, b, c int64, roundUp bool) int64 {
+	n := new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+	d := big.NewInt(c)
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if roundUp && r.Sign() > 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q.Int64()
+}
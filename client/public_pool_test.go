@@ -0,0 +1,34 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPoolState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("value") {
+		case "100":
+			// the pool shares its L1 address with account 7, but is operated by account 8
+			w.Write([]byte(`{"code":200,"accounts":[{"index":100,"l1_address":"0x01","total_asset_value":"1000","pool_info":{"operator_fee":"1.10","min_operator_share_rate":"0.29","total_shares":1000,"operator_shares":500,"operator_account_index":8}}]}`))
+		case "7":
+			w.Write([]byte(`{"code":200,"accounts":[{"index":7,"l1_address":"0x01"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	txClient := newTestTxClient(t, NewHTTPClient(server.URL))
+	state, err := txClient.PublicPool(100).State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.IsOperator {
+		t.Fatal("expected account 7 not to be the operator")
+	}
+	if state.OperatorFee != 11_000 || state.MinOperatorShareRate != 29 {
+		t.Fatalf("expected 11000 & 29, got %v & %v", state.OperatorFee, state.MinOperatorShareRate)
+	}
+}
@@ -0,0 +1,195 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+type RequoteOutcome uint8
+
+const (
+	// RequoteApplied means the old order was replaced by the new one
+	RequoteApplied RequoteOutcome = iota
+	// RequoteNotApplied means nothing changed, the old order is still resting
+	RequoteNotApplied
+	// RequotePartial means only one of cancel & create was executed, see RequoteResult.NextStep
+	RequotePartial
+	// RequoteDryRun means the txs were signed with TransactOpts.DryRun and not sent
+	RequoteDryRun
+	// RequoteUnknown means Lighter accepted the txs but their outcome couldn't be determined, see RequoteResult.NextStep
+	RequoteUnknown
+)
+
+func (o RequoteOutcome) String() string {
+	switch o {
+	case RequoteApplied:
+		return "applied"
+	case RequoteNotApplied:
+		return "not applied"
+	case RequotePartial:
+		return "partial"
+	case RequoteDryRun:
+		return "dry run"
+	case RequoteUnknown:
+		return "unknown"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(o))
+	}
+}
+
+type RequoteResult struct {
+	Outcome RequoteOutcome
+	// Modified is set once a ModifyOrder tx updating the order in place was sent
+	Modified     bool
+	ModifyTxHash string
+	CancelTxHash string
+	CreateTxHash string
+	// NextStep describes what needs to be done to reach a consistent state after a partial outcome
+	NextStep string
+}
+
+// Requote replaces the resting order with the given index, which was placed as current, with next.
+// If only BaseAmount, Price or TriggerPrice change, the order is modified in place. Otherwise the old order is
+// canceled and the new one created using consecutive nonces, submitted as a single batch.
+// Requote waits for the txs to be executed, failed or expired before reporting the outcome.
+func (c *TxClient) Requote(ctx context.Context, index int64, current, next *types.CreateOrderTxReq, ops *types.TransactOpts) (*RequoteResult, error) {
+	if canModifyOrder(current, next) {
+		return c.requoteModify(ctx, index, next, ops)
+	}
+	return c.requoteCancelCreate(ctx, index, current.MarketIndex, next, ops)
+}
+
+// canModifyOrder reports whether ModifyOrder can turn current into next, as it can only change BaseAmount, Price & TriggerPrice
+func canModifyOrder(current, next *types.CreateOrderTxReq) bool {
+	return current.MarketIndex == next.MarketIndex &&
+		current.IsAsk == next.IsAsk &&
+		current.Type == next.Type &&
+		current.TimeInForce == next.TimeInForce &&
+		current.ReduceOnly == next.ReduceOnly &&
+		current.OrderExpiry == next.OrderExpiry &&
+		(next.ClientOrderIndex == txtypes.NilClientOrderIndex || next.ClientOrderIndex == current.ClientOrderIndex)
+}
+
+func (c *TxClient) requoteModify(ctx context.Context, index int64, next *types.CreateOrderTxReq, ops *types.TransactOpts) (*RequoteResult, error) {
+	txInfo, err := c.GetModifyOrderTransaction(&types.ModifyOrderTxReq{
+		MarketIndex:  next.MarketIndex,
+		Index:        index,
+		BaseAmount:   next.BaseAmount,
+		Price:        next.Price,
+		TriggerPrice: next.TriggerPrice,
+	}, ops)
+	if err != nil {
+		return &RequoteResult{Outcome: RequoteNotApplied}, err
+	}
+
+	result := &RequoteResult{Outcome: RequoteNotApplied, ModifyTxHash: txInfo.GetTxHash()}
+	sent, err := c.SendTx(txInfo)
+	if err != nil {
+		if sent != nil && sent.TxHash != "" {
+			// accepted by Lighter, e.g. with ErrTxHashMismatch
+			result.Outcome = RequoteUnknown
+			result.Modified = true
+			result.ModifyTxHash = sent.TxHash
+			result.NextStep = "the modify was accepted but the send failed, check the status of its tx"
+		}
+		return result, err
+	}
+	result.Modified = true
+	result.ModifyTxHash = sent.TxHash
+	if sent.DryRun {
+		result.Outcome = RequoteDryRun
+		return result, nil
+	}
+
+	status, err := c.tracker.Wait(ctx, sent.TxHash, txInfo.ExpiredAt)
+	if err != nil {
+		result.Outcome = RequoteUnknown
+		result.NextStep = "the outcome of the modify is unknown, check the status of its tx"
+		return result, err
+	}
+	if status.State == TxStateExecuted {
+		result.Outcome = RequoteApplied
+	}
+	return result, nil
+}
+
+func (c *TxClient) requoteCancelCreate(ctx context.Context, index int64, marketIndex uint8, next *types.CreateOrderTxReq, ops *types.TransactOpts) (*RequoteResult, error) {
+	ops, err := c.FullFillDefaultOps(ops)
+	if err != nil {
+		return &RequoteResult{Outcome: RequoteNotApplied}, err
+	}
+	cancelOps := *ops
+	createOps := *ops
+	createNonce := *ops.Nonce + 1
+	createOps.Nonce = &createNonce
+
+	// the create is signed first, so a rejection by the risk checks happens before anything else is signed
+	createTx, err := c.GetCreateOrderTransaction(next, &createOps)
+	if err != nil {
+		return &RequoteResult{Outcome: RequoteNotApplied}, err
+	}
+	cancelTx, err := c.GetCancelOrderTransaction(&types.CancelOrderTxReq{
+		MarketIndex: marketIndex,
+		Index:       index,
+	}, &cancelOps)
+	if err != nil {
+		return &RequoteResult{Outcome: RequoteNotApplied}, err
+	}
+
+	result := &RequoteResult{
+		Outcome:      RequoteNotApplied,
+		CancelTxHash: cancelTx.GetTxHash(),
+		CreateTxHash: createTx.GetTxHash(),
+	}
+	sent, err := c.SendTxBatch([]txtypes.TxInfo{cancelTx, createTx})
+	if err != nil {
+		if len(sent) == 0 {
+			// the batch is rejected as a whole
+			return result, err
+		}
+		// Lighter accepted the batch but its response didn't match it, e.g. ErrTxHashMismatch. Hashes are returned in order.
+		result.Outcome = RequoteUnknown
+		result.CancelTxHash = sent[0].TxHash
+		if len(sent) > 1 {
+			result.CreateTxHash = sent[1].TxHash
+		}
+		result.NextStep = "the batch was accepted but the send failed, check the status of both txs"
+		return result, err
+	}
+	if sent[0].DryRun {
+		result.Outcome = RequoteDryRun
+		return result, nil
+	}
+
+	cancelStatus, err := c.tracker.Wait(ctx, sent[0].TxHash, ops.ExpiredAt)
+	if err != nil {
+		result.Outcome = RequoteUnknown
+		result.NextStep = "the outcome is unknown, check the status of both txs"
+		return result, err
+	}
+	createStatus, err := c.tracker.Wait(ctx, sent[1].TxHash, ops.ExpiredAt)
+	if err != nil {
+		result.Outcome = RequoteUnknown
+		result.NextStep = "the outcome of the create is unknown, check the status of its tx"
+		return result, err
+	}
+
+	canceled := cancelStatus.State == TxStateExecuted
+	created := createStatus.State == TxStateExecuted
+	switch {
+	case canceled && created:
+		result.Outcome = RequoteApplied
+	case !canceled && !created:
+		result.Outcome = RequoteNotApplied
+	case canceled:
+		result.Outcome = RequotePartial
+		result.NextStep = "the old order was canceled but the new one was not created, resubmit the create"
+	default:
+		result.Outcome = RequotePartial
+		result.NextStep = "the new order was created but the old one was not canceled, cancel it if it's still resting"
+	}
+	return result, nil
+}
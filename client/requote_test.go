@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+// newMismatchServer accepts every tx but answers with hashes which don't match the signed ones
+func newMismatchServer(t *testing.T) *HTTPClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/sendTx":
+			w.Write([]byte(`{"code":200,"tx_hash":"0xaa"}`))
+		case "/api/v1/sendTxBatch":
+			w.Write([]byte(`{"code":200,"tx_hash":["0xaa","0xbb"]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	apiClient := NewHTTPClient(server.URL)
+	apiClient.SetTxHashVerification(true)
+	return apiClient
+}
+
+func TestRequoteReportsAcceptedTxsAsUnknown(t *testing.T) {
+	txClient := newTestTxClient(t, newMismatchServer(t))
+	current := &types.CreateOrderTxReq{
+		MarketIndex:      0,
+		ClientOrderIndex: 1,
+		BaseAmount:       100,
+		Price:            1000,
+		Type:             txtypes.LimitOrder,
+		TimeInForce:      txtypes.GoodTillTime,
+		OrderExpiry:      time.Now().Add(time.Hour * 24).UnixMilli(),
+	}
+	nonce := int64(1)
+
+	modified := *current
+	modified.Price = 1001
+	result, err := txClient.Requote(context.Background(), 1, current, &modified, &types.TransactOpts{Nonce: &nonce})
+	if !errors.Is(err, ErrTxHashMismatch) {
+		t.Fatalf("expected ErrTxHashMismatch, got %v", err)
+	}
+	if result.Outcome != RequoteUnknown || !result.Modified || result.ModifyTxHash != "0xaa" {
+		t.Fatalf("unexpected modify result %+v", result)
+	}
+
+	replaced := *current
+	replaced.IsAsk = 1
+	result, err = txClient.Requote(context.Background(), 1, current, &replaced, &types.TransactOpts{Nonce: &nonce})
+	if !errors.Is(err, ErrTxHashMismatch) {
+		t.Fatalf("expected ErrTxHashMismatch, got %v", err)
+	}
+	if result.Outcome != RequoteUnknown || result.CancelTxHash != "0xaa" || result.CreateTxHash != "0xbb" {
+		t.Fatalf("unexpected cancel & create result %+v", result)
+	}
+}
+
+func TestRequoteReportsUntrackedTxsAsUnknown(t *testing.T) {
+	// 0xbb is always pending, 0xaa is executed once cancelExecuted is set
+	var cancelExecuted atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/sendTxBatch":
+			w.Write([]byte(`{"code":200,"tx_hash":["0xaa","0xbb"]}`))
+		case "/api/v1/tx":
+			hash, status := r.URL.Query().Get("value"), TxStatusPending
+			if hash == "0xaa" && cancelExecuted.Load() {
+				status = TxStatusExecuted
+			}
+			fmt.Fprintf(w, `{"code":200,"hash":%q,"status":%v}`, hash, status)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	apiClient := NewHTTPClient(server.URL)
+	txClient := newTestTxClient(t, apiClient)
+	txClient.SetTxTracker(NewTxTracker(apiClient, time.Millisecond))
+
+	current := &types.CreateOrderTxReq{
+		MarketIndex:      0,
+		ClientOrderIndex: 1,
+		BaseAmount:       100,
+		Price:            1000,
+		Type:             txtypes.LimitOrder,
+		TimeInForce:      txtypes.GoodTillTime,
+		OrderExpiry:      time.Now().Add(time.Hour * 24).UnixMilli(),
+	}
+	replaced := *current
+	replaced.IsAsk = 1
+	// waiting gives up on the first pending tx
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, executed := range []bool{false, true} {
+		cancelExecuted.Store(executed)
+		nonce := int64(1)
+		result, err := txClient.Requote(ctx, 1, current, &replaced, &types.TransactOpts{Nonce: &nonce})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("cancel executed %v: expected context.Canceled, got %v", executed, err)
+		}
+		if result.Outcome != RequoteUnknown || result.NextStep == "" {
+			t.Fatalf("cancel executed %v: unexpected result %+v", executed, result)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package client
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+// RiskLimits are enforced client side before CreateOrder, ModifyOrder & CreateGroupedOrders txs are signed.
+// Notional values are in USDC. Zero values disable the corresponding check.
+type RiskLimits struct {
+	MaxOrderNotional float64
+	// MaxMarketNotional caps the position value plus the notional of the resting orders in a single market
+	MaxMarketNotional float64
+	MaxOpenOrders     int64
+	// PriceBand is the max relative distance between the order price and the mid price, e.g. 0.05 for 5%
+	PriceBand float64
+	// MarketOrderPriceBand is the max relative distance between the worst price of a market order, its slippage bound,
+	// and the mid price. Market orders aren't checked against PriceBand, so zero leaves them out of the band check.
+	MarketOrderPriceBand float64
+	// MaxLeverage caps the value of all positions plus the new order relative to the account's total asset value.
+	// It's also enforced on UpdateLeverage txs.
+	MaxLeverage float64
+	// KillSwitchAllowsReduceOnly lets reduce only orders & modifications through while the kill switch is enabled,
+	// so positions can still be closed. By default the kill switch only allows cancels.
+	KillSwitchAllowsReduceOnly bool
+}
+
+// checksOrders reports whether any limit applies to the orders themselves
+func (l *RiskLimits) checksOrders() bool {
+	return l.MaxOrderNotional > 0 || l.MaxMarketNotional > 0 || l.MaxOpenOrders > 0 || l.PriceBand > 0 ||
+		l.MarketOrderPriceBand > 0 || l.MaxLeverage > 0
+}
+
+type riskOrder struct {
+	marketIndex uint8
+	baseAmount  int64
+	price       uint32
+	reduceOnly  bool
+	// the price of market orders is the worst price they can be filled at
+	market bool
+	// trigger orders rest away from the mid, so they're not checked against the price band
+	triggered bool
+	// replaces is the index of the order being modified, 0 for new orders
+	replaces int64
+}
+
+type riskChecker struct {
+	mu         sync.RWMutex
+	limits     *RiskLimits
+	killSwitch bool
+}
+
+func (r *riskChecker) state() (*RiskLimits, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.limits, r.killSwitch
+}
+
+// SetRiskLimits enables the client side risk checks. Pass nil to disable them.
+func (c *TxClient) SetRiskLimits(limits *RiskLimits) {
+	c.risk.mu.Lock()
+	defer c.risk.mu.Unlock()
+	c.risk.limits = limits
+}
+
+// SetKillSwitch blocks every new order & order modification while enabled, only allowing cancels.
+// Set RiskLimits.KillSwitchAllowsReduceOnly to also allow reduce only orders.
+func (c *TxClient) SetKillSwitch(enabled bool) {
+	c.risk.mu.Lock()
+	defer c.risk.mu.Unlock()
+	c.risk.killSwitch = enabled
+}
+
+func (c *TxClient) KillSwitch() bool {
+	_, killSwitch := c.risk.state()
+	return killSwitch
+}
+
+func newRiskOrder(order *types.CreateOrderTxReq) *riskOrder {
+	return &riskOrder{
+		marketIndex: order.MarketIndex,
+		baseAmount:  order.BaseAmount,
+		price:       order.Price,
+		reduceOnly:  order.ReduceOnly == 1,
+		market:      order.Type == txtypes.MarketOrder,
+		triggered:   order.TriggerPrice != txtypes.NilOrderTriggerPrice,
+	}
+}
+
+func (c *TxClient) checkOrderRisk(orders ...*riskOrder) error {
+	limits, killSwitch := c.risk.state()
+	if killSwitch {
+		allowsReduceOnly := limits != nil && limits.KillSwitchAllowsReduceOnly
+		for _, order := range orders {
+			if !allowsReduceOnly || !order.reduceOnly {
+				return ErrKillSwitchEnabled
+			}
+		}
+	}
+	if limits == nil || !limits.checksOrders() {
+		return nil
+	}
+	if c.apiClient == nil {
+		return fmt.Errorf("HTTPClient is nil, can't run risk checks")
+	}
+
+	newOrders := int64(0)
+	newNotional := 0.0
+	marketNotional := make(map[uint8]float64)
+	for _, order := range orders {
+		market, err := c.market(order.marketIndex)
+		if err != nil {
+			return err
+		}
+		price := toDecimal(int64(order.price), market.PriceDecimals)
+		notional := toDecimal(order.baseAmount, market.SizeDecimals) * price
+
+		if limits.MaxOrderNotional > 0 && notional > limits.MaxOrderNotional {
+			return fmt.Errorf("%w. notional: %.2f limit: %.2f", ErrOrderNotionalTooHigh, notional, limits.MaxOrderNotional)
+		}
+		band := limits.PriceBand
+		if order.market {
+			band = limits.MarketOrderPriceBand
+		}
+		if band > 0 && !order.triggered {
+			mid, err := c.midPrice(order.marketIndex)
+			if err != nil {
+				return err
+			}
+			if deviation := math.Abs(price-mid) / mid; deviation > band {
+				return fmt.Errorf("%w. price: %v mid: %v band: %v", ErrPriceOutsideBand, price, mid, band)
+			}
+		}
+
+		if order.replaces == 0 {
+			newOrders++
+		}
+		if !order.reduceOnly {
+			newNotional += notional
+			marketNotional[order.marketIndex] += notional
+		}
+	}
+
+	if limits.MaxOpenOrders <= 0 && limits.MaxMarketNotional <= 0 && limits.MaxLeverage <= 0 {
+		return nil
+	}
+	account, err := c.apiClient.GetAccount(c.accountIndex)
+	if err != nil {
+		return err
+	}
+
+	if limits.MaxOpenOrders > 0 && account.TotalOrderCount+newOrders > limits.MaxOpenOrders {
+		return fmt.Errorf("%w. open: %v new: %v limit: %v", ErrTooManyOpenOrders, account.TotalOrderCount, newOrders, limits.MaxOpenOrders)
+	}
+
+	if limits.MaxMarketNotional > 0 {
+		for marketIndex, notional := range marketNotional {
+			current, err := c.marketExposure(account, marketIndex, orders)
+			if err != nil {
+				return err
+			}
+			if current+notional > limits.MaxMarketNotional {
+				return fmt.Errorf("%w. market: %v current: %.2f new: %.2f limit: %.2f", ErrMarketNotionalTooHigh, marketIndex, current, notional, limits.MaxMarketNotional)
+			}
+		}
+	}
+
+	if limits.MaxLeverage > 0 && newNotional > 0 {
+		totalAssetValue, err := parseDecimal(account.TotalAssetValue)
+		if err != nil {
+			return err
+		}
+		positionValue := 0.0
+		for _, position := range account.Positions {
+			value, err := parseDecimal(position.PositionValue)
+			if err != nil {
+				return err
+			}
+			positionValue += math.Abs(value)
+		}
+		if totalAssetValue <= 0 {
+			return fmt.Errorf("%w. account has no assets", ErrLeverageTooHigh)
+		}
+		if leverage := (positionValue + newNotional) / totalAssetValue; leverage > limits.MaxLeverage {
+			return fmt.Errorf("%w. leverage: %.2f limit: %.2f", ErrLeverageTooHigh, leverage, limits.MaxLeverage)
+		}
+	}
+
+	return nil
+}
+
+// marketExposure returns the position value plus the notional of the resting orders in the market,
+// leaving out the orders which are being modified
+func (c *TxClient) marketExposure(account *Account, marketIndex uint8, orders []*riskOrder) (float64, error) {
+	exposure := 0.0
+	for _, position := range account.Positions {
+		if position.MarketIndex != marketIndex {
+			continue
+		}
+		value, err := parseDecimal(position.PositionValue)
+		if err != nil {
+			return 0, err
+		}
+		exposure += math.Abs(value)
+	}
+
+	auth, err := c.authToken()
+	if err != nil {
+		return 0, err
+	}
+	activeOrders, err := c.apiClient.GetActiveOrders(c.accountIndex, marketIndex, auth)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, activeOrder := range activeOrders {
+		modified := false
+		for _, order := range orders {
+			if order.replaces != 0 && (order.replaces == activeOrder.OrderIndex || order.replaces == activeOrder.ClientOrderIndex) {
+				modified = true
+			}
+		}
+		if modified || activeOrder.ReduceOnly {
+			continue
+		}
+		remaining, err := parseDecimal(activeOrder.RemainingBaseAmount)
+		if err != nil {
+			return 0, err
+		}
+		price, err := parseDecimal(activeOrder.Price)
+		if err != nil {
+			return 0, err
+		}
+		exposure += remaining * price
+	}
+	return exposure, nil
+}
+
+// midPrice returns the mid of the best bid & ask, or the best price of one side if the other one is empty
+func (c *TxClient) midPrice(marketIndex uint8) (float64, error) {
+	book, err := c.apiClient.GetOrderBookOrders(marketIndex, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	prices := make([]float64, 0, 2)
+	for _, side := range [][]*SimpleOrder{book.Asks, book.Bids} {
+		if len(side) == 0 {
+			continue
+		}
+		price, err := parseDecimal(side[0].Price)
+		if err != nil {
+			return 0, err
+		}
+		prices = append(prices, price)
+	}
+
+	switch len(prices) {
+	case 0:
+		return 0, fmt.Errorf("order book of market %v is empty, can't compute mid price", marketIndex)
+	case 1:
+		return prices[0], nil
+	default:
+		return (prices[0] + prices[1]) / 2, nil
+	}
+}
+
+func (c *TxClient) checkLeverageRisk(initialMarginFraction uint16) error {
+	limits, _ := c.risk.state()
+	if limits == nil || limits.MaxLeverage <= 0 || initialMarginFraction == 0 {
+		return nil
+	}
+	if leverage := float64(txtypes.MarginFractionTick) / float64(initialMarginFraction); leverage > limits.MaxLeverage {
+		return fmt.Errorf("%w. leverage: %.2f limit: %.2f", ErrLeverageTooHigh, leverage, limits.MaxLeverage)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+func TestKillSwitchBlocksOrders(t *testing.T) {
+	txClient := newTestTxClient(t, nil)
+	txClient.SetKillSwitch(true)
+
+	order := &types.CreateOrderTxReq{
+		MarketIndex:      0,
+		ClientOrderIndex: 1,
+		BaseAmount:       100,
+		Price:            1000,
+		IsAsk:            1,
+		Type:             txtypes.MarketOrder,
+		TimeInForce:      txtypes.ImmediateOrCancel,
+		OrderExpiry:      txtypes.NilOrderExpiry,
+	}
+	nonce := int64(1)
+	if _, err := txClient.GetCreateOrderTransaction(order, &types.TransactOpts{Nonce: &nonce}); !errors.Is(err, ErrKillSwitchEnabled) {
+		t.Fatalf("expected ErrKillSwitchEnabled, got %v", err)
+	}
+
+	// reduce only orders are only allowed once opted in
+	order.ReduceOnly = 1
+	if _, err := txClient.GetCreateOrderTransaction(order, &types.TransactOpts{Nonce: &nonce}); !errors.Is(err, ErrKillSwitchEnabled) {
+		t.Fatalf("expected ErrKillSwitchEnabled for a reduce only order, got %v", err)
+	}
+	txClient.SetRiskLimits(&RiskLimits{KillSwitchAllowsReduceOnly: true})
+	if _, err := txClient.GetCreateOrderTransaction(order, &types.TransactOpts{Nonce: &nonce}); err != nil {
+		t.Fatalf("expected reduce only order to be allowed, got %v", err)
+	}
+	if _, err := txClient.GetModifyOrderTransaction(&types.ModifyOrderTxReq{MarketIndex: 0, Index: 1, BaseAmount: 100, Price: 1000}, &types.TransactOpts{Nonce: &nonce}); !errors.Is(err, ErrKillSwitchEnabled) {
+		t.Fatalf("expected ErrKillSwitchEnabled for a modification which isn't reduce only, got %v", err)
+	}
+
+	// cancels are always allowed
+	if _, err := txClient.GetCancelOrderTransaction(&types.CancelOrderTxReq{MarketIndex: 0, Index: 1}, &types.TransactOpts{Nonce: &nonce}); err != nil {
+		t.Fatalf("expected cancel to be allowed, got %v", err)
+	}
+}
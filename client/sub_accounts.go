@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	curve "github.com/elliottech/poseidon_crypto/curve/ecgfp5"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+// L1Signer signs a message with the L1 (Ethereum) key owning the account, using personal_sign, and returns the 0x prefixed signature
+type L1Signer func(message string) (string, error)
+
+func signL1(tx L1SignedTx, l1Signer L1Signer) (string, error) {
+	sig, err := l1Signer(tx.GetL1SignatureBody())
+	if err != nil {
+		return "", fmt.Errorf("failed to sign L1 message. err: %w", err)
+	}
+	return sig, nil
+}
+
+// SubAccounts manages the sub accounts of the account the TxClient is linked to, which is expected to be the master account
+type SubAccounts struct {
+	txClient *TxClient
+}
+
+func (c *TxClient) SubAccounts() *SubAccounts {
+	return &SubAccounts{txClient: c}
+}
+
+// List returns the accounts owned by the same L1 address, except the master account itself
+func (s *SubAccounts) List() ([]*Account, error) {
+	c := s.txClient
+	if c.apiClient == nil {
+		return nil, fmt.Errorf("HTTPClient is nil, can't list sub accounts")
+	}
+	master, err := c.apiClient.GetAccount(c.accountIndex)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := c.apiClient.GetAccountsByL1Address(master.L1Address)
+	if err != nil {
+		return nil, err
+	}
+
+	subAccounts := make([]*Account, 0, len(accounts))
+	for _, account := range accounts {
+		if account.AccountIndex != c.accountIndex {
+			subAccounts = append(subAccounts, account)
+		}
+	}
+	return subAccounts, nil
+}
+
+// Create sends a CreateSubAccount tx and returns the index of the new sub account once the tx is executed
+func (s *SubAccounts) Create(ctx context.Context, ops *types.TransactOpts) (int64, error) {
+	txInfo, err := s.txClient.GetCreateSubAccountTransaction(ops)
+	if err != nil {
+		return 0, err
+	}
+	return s.sendAndFindNew(ctx, txInfo)
+}
+
+// sendAndFindNew sends a tx creating an account owned by the same L1 address, and returns the index of the new account once the tx is executed
+func (s *SubAccounts) sendAndFindNew(ctx context.Context, tx txtypes.TxInfo) (int64, error) {
+	c := s.txClient
+	if tx.IsDryRun() {
+		return 0, fmt.Errorf("accounts are not created in dry run mode")
+	}
+	before, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+	known := make(map[int64]struct{}, len(before))
+	for _, account := range before {
+		known[account.AccountIndex] = struct{}{}
+	}
+
+	result, err := c.SendAndWait(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	if result.State != TxStateExecuted {
+		return 0, fmt.Errorf("tx %s was %v", result.TxHash, result.State)
+	}
+
+	after, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+	accountIndex := int64(-1)
+	for _, account := range after {
+		if _, ok := known[account.AccountIndex]; !ok && account.AccountIndex > accountIndex {
+			accountIndex = account.AccountIndex
+		}
+	}
+	if accountIndex == -1 {
+		return 0, fmt.Errorf("tx %s executed but no new account was found", result.TxHash)
+	}
+	return accountIndex, nil
+}
+
+// ProvisionApiKey generates a new API key, registers it at apiKeyIndex of the sub account with a ChangePubKey tx signed
+// by both the new key and l1Signer, and returns a TxClient for it once the tx is executed.
+// The private key can be read from the returned client using GetKeyManager().PrvKeyBytes().
+func (s *SubAccounts) ProvisionApiKey(ctx context.Context, subAccountIndex int64, apiKeyIndex uint8, l1Signer L1Signer) (*TxClient, error) {
+	c := s.txClient
+	if l1Signer == nil {
+		return nil, fmt.Errorf("L1 signer is required to change the API key")
+	}
+
+	privateKey := curve.SampleScalar(nil)
+	subClient, err := NewTxClient(c.apiClient, hexutil.Encode(privateKey.ToLittleEndianBytes()), subAccountIndex, apiKeyIndex, c.chainId)
+	if err != nil {
+		return nil, err
+	}
+
+	txInfo, err := subClient.getChangePubKeyTransaction(&types.ChangePubKeyReq{PubKey: subClient.keyManager.PubKeyBytes()}, l1Signer, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := subClient.SendAndWait(ctx, txInfo)
+	if err != nil {
+		return nil, err
+	}
+	if result.State != TxStateExecuted {
+		return nil, fmt.Errorf("change pub key tx %s was %v", result.TxHash, result.State)
+	}
+	return subClient, nil
+}
+
+// Fund transfers amount USDC (6 decimals) from the master account to the sub account
+func (s *SubAccounts) Fund(ctx context.Context, subAccountIndex int64, amount int64, l1Signer L1Signer, ops *types.TransactOpts) (*TxResult, error) {
+	return s.txClient.Transfer(ctx, subAccountIndex, amount, [memoSize]byte{}, l1Signer, ops)
+}
+
+// Sweep transfers the whole available balance of the sub account, less the transfer fee, back to the master account.
+// subClient is linked to the sub account, e.g. the one returned by ProvisionApiKey.
+func (s *SubAccounts) Sweep(ctx context.Context, subClient *TxClient, l1Signer L1Signer, ops *types.TransactOpts) (*TxResult, error) {
+	if l1Signer == nil {
+		return nil, fmt.Errorf("L1 signer is required to transfer")
+	}
+	if subClient.apiClient == nil {
+		return nil, fmt.Errorf("HTTPClient is nil, can't get balance")
+	}
+	account, err := subClient.apiClient.GetAccount(subClient.accountIndex)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := parseDecimal(account.AvailableBalance)
+	if err != nil {
+		return nil, err
+	}
+	fee, err := subClient.TransferFee(s.txClient.accountIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := toTicks(balance, usdcDecimals) - fee
+	if amount <= 0 {
+		return nil, fmt.Errorf("available balance %v doesn't cover the transfer fee %v", account.AvailableBalance, toDecimal(fee, usdcDecimals))
+	}
+	// signed with the fee looked up above, so the amount & the fee always add up to the balance
+	txInfo, err := subClient.signTransfer(s.txClient.accountIndex, amount, fee, [memoSize]byte{}, l1Signer, ops)
+	if err != nil {
+		return nil, err
+	}
+	return subClient.sendTransfer(ctx, txInfo)
+}
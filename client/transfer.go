@@ -0,0 +1,201 @@
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+const (
+	memoSize = 32
+	// structuredMemoMarker starts every structured memo. It can't start a valid UTF-8 string, so text memos are never mistaken for structured ones.
+	structuredMemoMarker = 0xff
+	// MaxStructuredMemoTextLength is how many bytes of text fit in a structured memo, after the marker, kind & reference
+	MaxStructuredMemoTextLength = memoSize - 10
+)
+
+// StructuredMemo packs a kind, a numeric reference (e.g. an invoice or a user id) and a short text into a transfer memo
+type StructuredMemo struct {
+	Kind      uint8
+	Reference uint64
+	Text      string
+}
+
+// Memo is the decoded form of the 32 byte memo attached to a transfer. Zero padding is trimmed from Text.
+type Memo struct {
+	Raw [memoSize]byte
+	// Text is set for memos holding a UTF-8 string
+	Text string
+	// Structured is set for memos encoded with EncodeStructuredMemo
+	Structured *StructuredMemo
+}
+
+// EncodeMemo encodes a UTF-8 string of up to 32 bytes into a memo, zero padded
+func EncodeMemo(text string) ([memoSize]byte, error) {
+	memo := [memoSize]byte{}
+	if len(text) > memoSize {
+		return memo, fmt.Errorf("memo should be at most %v bytes but is %v", memoSize, len(text))
+	}
+	if !utf8.ValidString(text) {
+		return memo, fmt.Errorf("memo is not valid UTF-8")
+	}
+	if strings.ContainsRune(text, 0) {
+		return memo, fmt.Errorf("memo can't contain zero bytes")
+	}
+	copy(memo[:], text)
+	return memo, nil
+}
+
+// EncodeStructuredMemo encodes the memo as a marker byte, the kind, the big endian reference and up to 22 bytes of UTF-8 text
+func EncodeStructuredMemo(m *StructuredMemo) ([memoSize]byte, error) {
+	memo := [memoSize]byte{}
+	if len(m.Text) > MaxStructuredMemoTextLength {
+		return memo, fmt.Errorf("structured memo text should be at most %v bytes but is %v", MaxStructuredMemoTextLength, len(m.Text))
+	}
+	if !utf8.ValidString(m.Text) {
+		return memo, fmt.Errorf("structured memo text is not valid UTF-8")
+	}
+	if strings.ContainsRune(m.Text, 0) {
+		return memo, fmt.Errorf("structured memo text can't contain zero bytes")
+	}
+	memo[0] = structuredMemoMarker
+	memo[1] = m.Kind
+	binary.BigEndian.PutUint64(memo[2:10], m.Reference)
+	copy(memo[10:], m.Text)
+	return memo, nil
+}
+
+// DecodeMemo recognizes structured & text memos. Memos which are neither only have Raw set.
+func DecodeMemo(raw [memoSize]byte) *Memo {
+	memo := &Memo{Raw: raw}
+	if raw[0] == structuredMemoMarker {
+		text := strings.TrimRight(string(raw[10:]), "\x00")
+		if utf8.ValidString(text) && !strings.ContainsRune(text, 0) {
+			memo.Structured = &StructuredMemo{
+				Kind:      raw[1],
+				Reference: binary.BigEndian.Uint64(raw[2:10]),
+				Text:      text,
+			}
+		}
+		return memo
+	}
+
+	text := strings.TrimRight(string(raw[:]), "\x00")
+	if utf8.ValidString(text) && !strings.ContainsRune(text, 0) {
+		memo.Text = text
+	}
+	return memo
+}
+
+// DecodeHexMemo decodes a 0x prefixed or bare hex memo, as returned by the API
+func DecodeHexMemo(hexMemo string) (*Memo, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(hexMemo, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > memoSize {
+		return nil, fmt.Errorf("memo should be at most %v bytes but is %v", memoSize, len(b))
+	}
+	raw := [memoSize]byte{}
+	copy(raw[:], b)
+	return DecodeMemo(raw), nil
+}
+
+// TransferFee returns the current USDC (6 decimals) fee of a transfer from the account to toAccountIndex
+func (c *TxClient) TransferFee(toAccountIndex int64) (int64, error) {
+	if c.apiClient == nil {
+		return 0, fmt.Errorf("HTTPClient is nil, can't get transfer fee")
+	}
+	auth, err := c.authToken()
+	if err != nil {
+		return 0, err
+	}
+	feeInfo, err := c.apiClient.GetTransferFeeInfo(c.accountIndex, toAccountIndex, auth)
+	if err != nil {
+		return 0, err
+	}
+	return feeInfo.TransferFee, nil
+}
+
+// SignTransfer signs a transfer of amount USDC (6 decimals) to toAccountIndex, paying the current fee.
+// l1Signer adds the L1 signature required by transfers; without it the tx has to be signed using GetL1SignatureBody before sending.
+func (c *TxClient) SignTransfer(toAccountIndex int64, amount int64, memo [memoSize]byte, l1Signer L1Signer, ops *types.TransactOpts) (*txtypes.L2TransferTxInfo, error) {
+	fee, err := c.TransferFee(toAccountIndex)
+	if err != nil {
+		return nil, err
+	}
+	return c.signTransfer(toAccountIndex, amount, fee, memo, l1Signer, ops)
+}
+
+// signTransfer signs a transfer paying fee, for callers which already looked it up
+func (c *TxClient) signTransfer(toAccountIndex int64, amount int64, fee int64, memo [memoSize]byte, l1Signer L1Signer, ops *types.TransactOpts) (*txtypes.L2TransferTxInfo, error) {
+	return c.getTransferTransaction(&types.TransferTxReq{
+		ToAccountIndex: toAccountIndex,
+		USDCAmount:     amount,
+		Fee:            fee,
+		Memo:           memo,
+	}, l1Signer, ops)
+}
+
+// Transfer sends amount USDC (6 decimals) to toAccountIndex, with a memo built by EncodeMemo or EncodeStructuredMemo, and waits for the tx to execute
+func (c *TxClient) Transfer(ctx context.Context, toAccountIndex int64, amount int64, memo [memoSize]byte, l1Signer L1Signer, ops *types.TransactOpts) (*TxResult, error) {
+	if l1Signer == nil {
+		return nil, fmt.Errorf("L1 signer is required to transfer")
+	}
+	txInfo, err := c.SignTransfer(toAccountIndex, amount, memo, l1Signer, ops)
+	if err != nil {
+		return nil, err
+	}
+	return c.sendTransfer(ctx, txInfo)
+}
+
+// sendTransfer sends the signed transfer and waits for it to execute
+func (c *TxClient) sendTransfer(ctx context.Context, txInfo *txtypes.L2TransferTxInfo) (*TxResult, error) {
+	result, err := c.SendAndWait(ctx, txInfo)
+	if err != nil {
+		return nil, err
+	}
+	if result.State != TxStateExecuted && result.State != TxStateDryRun {
+		return result, fmt.Errorf("transfer tx %s was %v", result.TxHash, result.State)
+	}
+	return result, nil
+}
+
+type ReceivedTransfer struct {
+	*TransferHistoryItem
+	Memo *Memo
+}
+
+// ReceivedTransfers returns the latest transfers into the account, with their memos decoded
+func (c *TxClient) ReceivedTransfers() ([]*ReceivedTransfer, error) {
+	if c.apiClient == nil {
+		return nil, fmt.Errorf("HTTPClient is nil, can't get transfers")
+	}
+	auth, err := c.authToken()
+	if err != nil {
+		return nil, err
+	}
+	transfers, err := c.apiClient.GetTransferHistory(c.accountIndex, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	received := make([]*ReceivedTransfer, 0, len(transfers))
+	for _, transfer := range transfers {
+		if transfer.ToAccountIndex != c.accountIndex {
+			continue
+		}
+		memo, err := DecodeHexMemo(transfer.Memo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode memo of transfer %s. err: %w", transfer.TxHash, err)
+		}
+		received = append(received, &ReceivedTransfer{TransferHistoryItem: transfer, Memo: memo})
+	}
+	return received, nil
+}
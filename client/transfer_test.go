@@ -0,0 +1,150 @@
+package client
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestEncodeMemo(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		err  bool
+	}{
+		{name: "empty", text: ""},
+		{name: "ascii", text: "invoice 42"},
+		{name: "multi byte", text: "paiement reçu ✓"},
+		{name: "32 bytes", text: strings.Repeat("a", 32)},
+		{name: "33 bytes", text: strings.Repeat("a", 33), err: true},
+		// 11 3 byte runes are 33 bytes even though they're 11 characters
+		{name: "33 bytes of runes", text: strings.Repeat("✓", 11), err: true},
+		{name: "invalid UTF-8", text: "\xff\xfe", err: true},
+		{name: "zero byte", text: "a\x00b", err: true},
+	}
+	for _, tt := range tests {
+		memo, err := EncodeMemo(tt.text)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("%s: expected an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		// the text is zero padded to 32 bytes
+		if string(memo[:len(tt.text)]) != tt.text || strings.Trim(string(memo[len(tt.text):]), "\x00") != "" {
+			t.Fatalf("%s: unexpected memo %x", tt.name, memo)
+		}
+
+		decoded := DecodeMemo(memo)
+		if decoded.Text != tt.text || decoded.Structured != nil || decoded.Raw != memo {
+			t.Fatalf("%s: decoded %+v", tt.name, decoded)
+		}
+	}
+}
+
+func TestEncodeStructuredMemo(t *testing.T) {
+	tests := []struct {
+		name string
+		memo StructuredMemo
+		err  bool
+	}{
+		{name: "empty", memo: StructuredMemo{}},
+		{name: "reference only", memo: StructuredMemo{Kind: 1, Reference: 1<<64 - 1}},
+		{name: "text", memo: StructuredMemo{Kind: 2, Reference: 42, Text: "refund"}},
+		{name: "22 bytes", memo: StructuredMemo{Kind: 3, Reference: 7, Text: strings.Repeat("b", 22)}},
+		{name: "23 bytes", memo: StructuredMemo{Text: strings.Repeat("b", 23)}, err: true},
+		{name: "invalid UTF-8", memo: StructuredMemo{Text: "\xff"}, err: true},
+		{name: "zero byte", memo: StructuredMemo{Text: "a\x00"}, err: true},
+	}
+	for _, tt := range tests {
+		memo, err := EncodeStructuredMemo(&tt.memo)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("%s: expected an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if memo[0] != structuredMemoMarker || memo[1] != tt.memo.Kind {
+			t.Fatalf("%s: unexpected memo %x", tt.name, memo)
+		}
+
+		decoded := DecodeMemo(memo)
+		if decoded.Structured == nil || *decoded.Structured != tt.memo || decoded.Text != "" {
+			t.Fatalf("%s: decoded %+v", tt.name, decoded)
+		}
+	}
+}
+
+func TestDecodeMemo(t *testing.T) {
+	// bytes which are neither a text nor a structured memo
+	invalid := [memoSize]byte{0x80, 0x81}
+	if decoded := DecodeMemo(invalid); decoded.Text != "" || decoded.Structured != nil {
+		t.Fatalf("expected a raw memo, got %+v", decoded)
+	}
+	// a zero byte in the middle of a text isn't padding
+	gap := [memoSize]byte{'a', 0, 'b'}
+	if decoded := DecodeMemo(gap); decoded.Text != "" {
+		t.Fatalf("expected a raw memo, got %+v", decoded)
+	}
+	badStructured := [memoSize]byte{structuredMemoMarker, 1}
+	badStructured[10] = 0xfe
+	if decoded := DecodeMemo(badStructured); decoded.Structured != nil || decoded.Text != "" {
+		t.Fatalf("expected a raw memo, got %+v", decoded)
+	}
+}
+
+func TestDecodeHexMemo(t *testing.T) {
+	text, err := EncodeMemo("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	structured, err := EncodeStructuredMemo(&StructuredMemo{Kind: 9, Reference: 1234, Text: "order"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		hexMemo    string
+		text       string
+		structured bool
+		err        bool
+	}{
+		{name: "prefixed", hexMemo: "0x" + hex.EncodeToString(text[:]), text: "hello"},
+		{name: "bare", hexMemo: hex.EncodeToString(text[:]), text: "hello"},
+		// shorter memos are zero padded
+		{name: "short", hexMemo: "0x" + hex.EncodeToString([]byte("hi")), text: "hi"},
+		{name: "empty", hexMemo: "0x", text: ""},
+		{name: "structured", hexMemo: "0x" + hex.EncodeToString(structured[:]), structured: true},
+		{name: "too long", hexMemo: "0x" + strings.Repeat("61", 33), err: true},
+		{name: "not hex", hexMemo: "0xzz", err: true},
+		{name: "odd length", hexMemo: "0x616", err: true},
+	}
+	for _, tt := range tests {
+		memo, err := DecodeHexMemo(tt.hexMemo)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("%s: expected an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if tt.structured {
+			if memo.Raw != structured || memo.Structured == nil || memo.Structured.Reference != 1234 || memo.Structured.Text != "order" {
+				t.Fatalf("%s: decoded %+v", tt.name, memo)
+			}
+			continue
+		}
+		if memo.Text != tt.text || memo.Structured != nil {
+			t.Fatalf("%s: decoded %+v", tt.name, memo)
+		}
+	}
+}
@@ -3,14 +3,17 @@ package client
 import (
 	"encoding/hex"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/uncle-gua/lighter-go/signer"
 	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
 )
 
 const (
 	defaultExpireTime = time.Minute*10 - time.Second // we need to give a second margin, to eliminate millisecond differences
+	defaultAuthExpiry = time.Minute * 10
 )
 
 type TxClient struct {
@@ -19,6 +22,12 @@ type TxClient struct {
 	keyManager   signer.KeyManager
 	accountIndex int64
 	apiKeyIndex  uint8
+	tracker      *TxTracker
+	journal      *Journal
+	risk         *riskChecker
+
+	mu      sync.Mutex
+	markets map[uint8]*OrderBookDetail
 }
 
 // NewTxClient is linked to a specific (account, apiKey) pair
@@ -47,6 +56,9 @@ func NewTxClient(apiClient *HTTPClient, apiKeyPrivateKey string, accountIndex in
 		accountIndex: accountIndex,
 		chainId:      chainId,
 		keyManager:   keyManager,
+		tracker:      NewTxTracker(apiClient, defaultPollInterval),
+		risk:         &riskChecker{},
+		markets:      make(map[uint8]*OrderBookDetail),
 	}, nil
 }
 
@@ -63,6 +75,11 @@ func (c *TxClient) FullFillDefaultOps(ops *types.TransactOpts) (*types.TransactO
 	if ops.ApiKeyIndex == nil {
 		ops.ApiKeyIndex = &c.apiKeyIndex
 	}
+	if ops.Nonce == nil && ops.DryRun && c.apiClient == nil {
+		// a dry run never reaches Lighter, so any nonce will do when there's no way to fetch the next one
+		nonce := txtypes.MinNonce
+		ops.Nonce = &nonce
+	}
 	if ops.Nonce == nil {
 		if c.apiClient == nil {
 			return nil, fmt.Errorf("nonce was not provided & HTTPClient is nil. Either provide the nonce or enable HTTPClient to get the nonce from Lighter")
@@ -77,6 +94,40 @@ func (c *TxClient) FullFillDefaultOps(ops *types.TransactOpts) (*types.TransactO
 	return ops, nil
 }
 
+// afterSign is called with every tx produced by the client
+func (c *TxClient) afterSign(tx txtypes.TxInfo, ops *types.TransactOpts) error {
+	// dry runs are never sent, so they're kept out of the journal
+	if ops.DryRun || c.journal == nil {
+		return nil
+	}
+	if err := c.journal.appendSigned(tx); err != nil {
+		return fmt.Errorf("failed to journal signed tx. err: %w", err)
+	}
+	return nil
+}
+
+// market returns the market details, which are cached as size & price decimals don't change
+func (c *TxClient) market(marketIndex uint8) (*OrderBookDetail, error) {
+	c.mu.Lock()
+	market, ok := c.markets[marketIndex]
+	c.mu.Unlock()
+	if ok {
+		return market, nil
+	}
+
+	if c.apiClient == nil {
+		return nil, fmt.Errorf("HTTPClient is nil, can't get market details")
+	}
+	market, err := c.apiClient.GetOrderBookDetail(marketIndex)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.markets[marketIndex] = market
+	c.mu.Unlock()
+	return market, nil
+}
+
 func (c *TxClient) GetAccountIndex() int64 {
 	return c.accountIndex
 }
@@ -96,10 +147,24 @@ func (c *TxClient) GetAuthToken(deadline time.Time) (string, error) {
 	})
 }
 
+// authToken creates a short-lived auth token, used for the authenticated queries made by the client itself
+func (c *TxClient) authToken() (string, error) {
+	return c.GetAuthToken(time.Now().Add(defaultAuthExpiry))
+}
+
 func (c *TxClient) HTTP() *HTTPClient {
 	return c.apiClient
 }
 
+func (c *TxClient) SetTxTracker(tracker *TxTracker) {
+	c.tracker = tracker
+}
+
+// SetJournal makes the client journal every tx it signs, along with the result of sending it through SendTx
+func (c *TxClient) SetJournal(journal *Journal) {
+	c.journal = journal
+}
+
 func (c *TxClient) SwitchAPIKey(apiKey uint8) {
 	c.apiKeyIndex = apiKey
 }
@@ -9,6 +9,11 @@ import (
 )
 
 func (c *TxClient) GetChangePubKeyTransaction(tx *types.ChangePubKeyReq, ops *types.TransactOpts) (*txtypes.L2ChangePubKeyTxInfo, error) {
+	return c.getChangePubKeyTransaction(tx, nil, ops)
+}
+
+// getChangePubKeyTransaction adds the signature of l1Signer, if any, before the tx is journaled by afterSign
+func (c *TxClient) getChangePubKeyTransaction(tx *types.ChangePubKeyReq, l1Signer L1Signer, ops *types.TransactOpts) (*txtypes.L2ChangePubKeyTxInfo, error) {
 	ops, err := c.FullFillDefaultOps(ops)
 	if err != nil {
 		return nil, err
@@ -24,7 +29,14 @@ func (c *TxClient) GetChangePubKeyTransaction(tx *types.ChangePubKeyReq, ops *ty
 	if err := schnorr.Validate(pk[:], msgHash, txInfo.Sig); err != nil {
 		return nil, fmt.Errorf("failed to validate signature. error: %v", err)
 	}
-
+	if l1Signer != nil {
+		if txInfo.L1Sig, err = signL1(txInfo, l1Signer); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.afterSign(txInfo, ops); err != nil {
+		return nil, err
+	}
 	return txInfo, nil
 }
 
@@ -37,6 +49,9 @@ func (c *TxClient) GetCreateSubAccountTransaction(ops *types.TransactOpts) (*txt
 	if err != nil {
 		return nil, err
 	}
+	if err := c.afterSign(txInfo, ops); err != nil {
+		return nil, err
+	}
 	return txInfo, nil
 }
 
@@ -49,6 +64,9 @@ func (c *TxClient) GetCreatePublicPoolTransaction(tx *types.CreatePublicPoolTxRe
 	if err != nil {
 		return nil, err
 	}
+	if err := c.afterSign(txInfo, ops); err != nil {
+		return nil, err
+	}
 	return txInfo, nil
 }
 
@@ -61,10 +79,18 @@ func (c *TxClient) GetUpdatePublicPoolTransaction(tx *types.UpdatePublicPoolTxRe
 	if err != nil {
 		return nil, err
 	}
+	if err := c.afterSign(txInfo, ops); err != nil {
+		return nil, err
+	}
 	return txInfo, nil
 }
 
 func (c *TxClient) GetTransferTransaction(tx *types.TransferTxReq, ops *types.TransactOpts) (*txtypes.L2TransferTxInfo, error) {
+	return c.getTransferTransaction(tx, nil, ops)
+}
+
+// getTransferTransaction adds the signature of l1Signer, if any, before the tx is journaled by afterSign
+func (c *TxClient) getTransferTransaction(tx *types.TransferTxReq, l1Signer L1Signer, ops *types.TransactOpts) (*txtypes.L2TransferTxInfo, error) {
 	ops, err := c.FullFillDefaultOps(ops)
 	if err != nil {
 		return nil, err
@@ -73,6 +99,14 @@ func (c *TxClient) GetTransferTransaction(tx *types.TransferTxReq, ops *types.Tr
 	if err != nil {
 		return nil, err
 	}
+	if l1Signer != nil {
+		if txInfo.L1Sig, err = signL1(txInfo, l1Signer); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.afterSign(txInfo, ops); err != nil {
+		return nil, err
+	}
 	return txInfo, nil
 }
 
@@ -85,11 +119,16 @@ func (c *TxClient) GetWithdrawTransaction(tx *types.WithdrawTxReq, ops *types.Tr
 	if err != nil {
 		return nil, err
 	}
-
+	if err := c.afterSign(txInfo, ops); err != nil {
+		return nil, err
+	}
 	return txInfo, nil
 }
 
 func (c *TxClient) GetCreateOrderTransaction(tx *types.CreateOrderTxReq, ops *types.TransactOpts) (*txtypes.L2CreateOrderTxInfo, error) {
+	if err := c.checkOrderRisk(newRiskOrder(tx)); err != nil {
+		return nil, err
+	}
 	ops, err := c.FullFillDefaultOps(ops)
 	if err != nil {
 		return nil, err
@@ -98,6 +137,31 @@ func (c *TxClient) GetCreateOrderTransaction(tx *types.CreateOrderTxReq, ops *ty
 	if err != nil {
 		return nil, err
 	}
+	if err := c.afterSign(txInfo, ops); err != nil {
+		return nil, err
+	}
+	return txInfo, nil
+}
+
+func (c *TxClient) GetCreateGroupedOrdersTransaction(tx *types.CreateGroupedOrdersTxReq, ops *types.TransactOpts) (*txtypes.L2CreateGroupedOrdersTxInfo, error) {
+	orders := make([]*riskOrder, 0, len(tx.Orders))
+	for _, order := range tx.Orders {
+		orders = append(orders, newRiskOrder(order))
+	}
+	if err := c.checkOrderRisk(orders...); err != nil {
+		return nil, err
+	}
+	ops, err := c.FullFillDefaultOps(ops)
+	if err != nil {
+		return nil, err
+	}
+	txInfo, err := types.ConstructL2CreateGroupedOrdersTx(c.keyManager, c.chainId, tx, ops)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.afterSign(txInfo, ops); err != nil {
+		return nil, err
+	}
 	return txInfo, nil
 }
 
@@ -110,11 +174,24 @@ func (c *TxClient) GetCancelOrderTransaction(tx *types.CancelOrderTxReq, ops *ty
 	if err != nil {
 		return nil, err
 	}
+	if err := c.afterSign(txInfo, ops); err != nil {
+		return nil, err
+	}
 	return txInfo, nil
 }
 
 func (c *TxClient) GetModifyOrderTransaction(tx *types.ModifyOrderTxReq, ops *types.TransactOpts) (*txtypes.L2ModifyOrderTxInfo, error) {
-	ops, err := c.FullFillDefaultOps(ops)
+	err := c.checkOrderRisk(&riskOrder{
+		marketIndex: tx.MarketIndex,
+		baseAmount:  tx.BaseAmount,
+		price:       tx.Price,
+		triggered:   tx.TriggerPrice != txtypes.NilOrderTriggerPrice,
+		replaces:    tx.Index,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ops, err = c.FullFillDefaultOps(ops)
 	if err != nil {
 		return nil, err
 	}
@@ -123,7 +200,9 @@ func (c *TxClient) GetModifyOrderTransaction(tx *types.ModifyOrderTxReq, ops *ty
 	if err != nil {
 		return nil, err
 	}
-
+	if err := c.afterSign(txInfo, ops); err != nil {
+		return nil, err
+	}
 	return txInfo, nil
 }
 
@@ -136,6 +215,9 @@ func (c *TxClient) GetCancelAllOrdersTransaction(tx *types.CancelAllOrdersTxReq,
 	if err != nil {
 		return nil, err
 	}
+	if err := c.afterSign(txInfo, ops); err != nil {
+		return nil, err
+	}
 	return txInfo, nil
 }
 
@@ -148,6 +230,9 @@ func (c *TxClient) GetMintSharesTransaction(tx *types.MintSharesTxReq, ops *type
 	if err != nil {
 		return nil, err
 	}
+	if err := c.afterSign(txInfo, ops); err != nil {
+		return nil, err
+	}
 	return txInfo, nil
 }
 
@@ -160,10 +245,16 @@ func (c *TxClient) GetBurnSharesTransaction(tx *types.BurnSharesTxReq, ops *type
 	if err != nil {
 		return nil, err
 	}
+	if err := c.afterSign(txInfo, ops); err != nil {
+		return nil, err
+	}
 	return txInfo, nil
 }
 
 func (c *TxClient) GetUpdateLeverageTransaction(tx *types.UpdateLeverageTxReq, ops *types.TransactOpts) (*txtypes.L2UpdateLeverageTxInfo, error) {
+	if err := c.checkLeverageRisk(tx.InitialMarginFraction); err != nil {
+		return nil, err
+	}
 	ops, err := c.FullFillDefaultOps(ops)
 	if err != nil {
 		return nil, err
@@ -172,21 +263,23 @@ func (c *TxClient) GetUpdateLeverageTransaction(tx *types.UpdateLeverageTxReq, o
 	if err != nil {
 		return nil, err
 	}
+	if err := c.afterSign(txInfo, ops); err != nil {
+		return nil, err
+	}
 	return txInfo, nil
 }
 
 func (c *TxClient) GetUpdateMarginTransaction(tx *types.UpdateMarginTxReq, ops *types.TransactOpts) (*txtypes.L2UpdateMarginTxInfo, error) {
-	if c.keyManager == nil {
-		return nil, fmt.Errorf("key manager is nil")
-	}
-
-	if ops == nil {
-		ops = new(types.TransactOpts)
+	ops, err := c.FullFillDefaultOps(ops)
+	if err != nil {
+		return nil, err
 	}
-
 	txInfo, err := types.ConstructUpdateMarginTx(c.keyManager, c.chainId, tx, ops)
 	if err != nil {
 		return nil, err
 	}
+	if err := c.afterSign(txInfo, ops); err != nil {
+		return nil, err
+	}
 	return txInfo, nil
 }
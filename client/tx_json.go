@@ -0,0 +1,57 @@
+package client
+
+import (
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+// L1SignedTx is implemented by the txs which also need a signature of GetL1SignatureBody by the L1 address owning the account
+type L1SignedTx interface {
+	txtypes.TxInfo
+	GetL1SignatureBody() string
+}
+
+// SignTxJSON signs a tx of txType given its request as JSON, decoded by types.DecodeTxRequest: the fields of the matching
+// types.*TxReq, with ChangePubKey's PubKey & Transfer's Memo as 0x prefixed hex strings.
+// The optional Nonce & ExpiredAt fields are used as TransactOpts; otherwise the defaults apply.
+func (c *TxClient) SignTxJSON(txType uint8, request []byte) (txtypes.TxInfo, error) {
+	req, err := types.DecodeTxRequest(txType, request)
+	if err != nil {
+		return nil, err
+	}
+	ops := &types.TransactOpts{Nonce: req.Nonce, ExpiredAt: req.ExpiredAt}
+
+	// the Get*Transaction functions fill the default ops & run the risk checks
+	switch tx := req.Req.(type) {
+	case *types.ChangePubKeyReq:
+		return c.GetChangePubKeyTransaction(tx, ops)
+	case *types.CreatePublicPoolTxReq:
+		return c.GetCreatePublicPoolTransaction(tx, ops)
+	case *types.UpdatePublicPoolTxReq:
+		return c.GetUpdatePublicPoolTransaction(tx, ops)
+	case *types.TransferTxReq:
+		return c.GetTransferTransaction(tx, ops)
+	case *types.WithdrawTxReq:
+		return c.GetWithdrawTransaction(tx, ops)
+	case *types.CreateOrderTxReq:
+		return c.GetCreateOrderTransaction(tx, ops)
+	case *types.CreateGroupedOrdersTxReq:
+		return c.GetCreateGroupedOrdersTransaction(tx, ops)
+	case *types.CancelOrderTxReq:
+		return c.GetCancelOrderTransaction(tx, ops)
+	case *types.CancelAllOrdersTxReq:
+		return c.GetCancelAllOrdersTransaction(tx, ops)
+	case *types.ModifyOrderTxReq:
+		return c.GetModifyOrderTransaction(tx, ops)
+	case *types.MintSharesTxReq:
+		return c.GetMintSharesTransaction(tx, ops)
+	case *types.BurnSharesTxReq:
+		return c.GetBurnSharesTransaction(tx, ops)
+	case *types.UpdateLeverageTxReq:
+		return c.GetUpdateLeverageTransaction(tx, ops)
+	case *types.UpdateMarginTxReq:
+		return c.GetUpdateMarginTransaction(tx, ops)
+	}
+	// CreateSubAccount has no field
+	return c.GetCreateSubAccountTransaction(ops)
+}
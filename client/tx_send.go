@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+const (
+	inactiveOrdersLookupLimit = 100
+)
+
+type SendTxResult struct {
+	TxHash string
+	// DryRun is set when the tx was signed with TransactOpts.DryRun, in which case nothing was sent
+	DryRun bool
+	// Body is the form body which would have been posted to api/v1/sendTx. Only set for dry runs.
+	Body string
+}
+
+// SendTx submits a tx signed by this client and returns the TxHash received from Lighter.
+// Txs signed with TransactOpts.DryRun are never sent; the locally computed hash and the form body are returned instead.
+func (c *TxClient) SendTx(tx txtypes.TxInfo) (*SendTxResult, error) {
+	if tx.IsDryRun() {
+		return c.simulateSend(tx)
+	}
+
+	if c.apiClient == nil {
+		return nil, fmt.Errorf("HTTPClient is nil, can't send tx")
+	}
+	txHash, err := c.apiClient.SendRawTx(tx)
+	if c.journal != nil {
+		if jErr := c.journal.appendSent(tx, txHash, err); jErr != nil && err == nil {
+			err = fmt.Errorf("tx was sent but journaling the result failed. err: %w", jErr)
+		}
+	}
+	if err != nil {
+		if txHash != "" {
+			return &SendTxResult{TxHash: txHash}, err
+		}
+		return nil, err
+	}
+	return &SendTxResult{TxHash: txHash}, nil
+}
+
+// SendTxBatch submits the txs in a single request, in order. Either all of them or none must be dry runs.
+func (c *TxClient) SendTxBatch(txs []txtypes.TxInfo) ([]*SendTxResult, error) {
+	dryRuns := 0
+	for _, tx := range txs {
+		if tx.IsDryRun() {
+			dryRuns++
+		}
+	}
+	if dryRuns != 0 && dryRuns != len(txs) {
+		return nil, fmt.Errorf("can't mix dry run txs with regular ones in a batch")
+	}
+
+	results := make([]*SendTxResult, 0, len(txs))
+	if dryRuns != 0 {
+		for _, tx := range txs {
+			result, err := c.simulateSend(tx)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, result)
+		}
+		return results, nil
+	}
+
+	if c.apiClient == nil {
+		return nil, fmt.Errorf("HTTPClient is nil, can't send txs")
+	}
+	txHashes, err := c.apiClient.SendRawTxBatch(txs)
+	if c.journal != nil {
+		for i, tx := range txs {
+			txHash := ""
+			if i < len(txHashes) {
+				txHash = txHashes[i]
+			}
+			if jErr := c.journal.appendSent(tx, txHash, err); jErr != nil && err == nil {
+				err = fmt.Errorf("txs were sent but journaling the result failed. err: %w", jErr)
+			}
+		}
+	}
+	for _, txHash := range txHashes {
+		results = append(results, &SendTxResult{TxHash: txHash})
+	}
+	return results, err
+}
+
+func (c *TxClient) simulateSend(tx txtypes.TxInfo) (*SendTxResult, error) {
+	txInfo, err := tx.GetTxInfo()
+	if err != nil {
+		return nil, err
+	}
+	priceProtection := true
+	if c.apiClient != nil {
+		priceProtection = c.apiClient.fatFingerProtection
+	}
+
+	return &SendTxResult{
+		TxHash: tx.GetTxHash(),
+		DryRun: true,
+		Body:   sendTxForm(tx.GetTxType(), txInfo, priceProtection).Encode(),
+	}, nil
+}
+
+// SendAndWait submits the tx and blocks until it's executed, failed or expired past its ExpiredAt.
+// For CreateOrder txs with a ClientOrderIndex, the resulting OrderIndex is looked up once the tx is executed.
+// Dry run txs return right away with TxStateDryRun.
+//
+// When Lighter accepted the tx but SendTx still returned an error, e.g. ErrTxHashMismatch or a journaling failure,
+// the returned hash is tracked all the same and the error is returned along with the result, so the tx isn't mistaken
+// for a rejected one. If tracking fails, the result has TxStateUnknown.
+func (c *TxClient) SendAndWait(ctx context.Context, tx txtypes.TxInfo) (*TxResult, error) {
+	meta, err := parseTxMeta(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	sent, sendErr := c.SendTx(tx)
+	if sendErr != nil && (sent == nil || sent.TxHash == "") {
+		return nil, sendErr
+	}
+	if sent.DryRun {
+		return &TxResult{TxHash: sent.TxHash, State: TxStateDryRun}, nil
+	}
+
+	result, err := c.tracker.Wait(ctx, sent.TxHash, meta.ExpiredAt)
+	if err != nil {
+		return &TxResult{TxHash: sent.TxHash, State: TxStateUnknown}, errors.Join(sendErr, err)
+	}
+
+	if result.State != TxStateExecuted {
+		return result, sendErr
+	}
+	if order, ok := tx.(*txtypes.L2CreateOrderTxInfo); ok && order.ClientOrderIndex != txtypes.NilClientOrderIndex {
+		result.OrderIndex, err = c.findOrderIndex(order.MarketIndex, order.ClientOrderIndex)
+		if err != nil {
+			return result, errors.Join(sendErr, fmt.Errorf("tx executed but failed to get order index. err: %w", err))
+		}
+	}
+
+	return result, sendErr
+}
+
+func (c *TxClient) findOrderIndex(marketIndex uint8, clientOrderIndex int64) (int64, error) {
+	auth, err := c.authToken()
+	if err != nil {
+		return 0, err
+	}
+
+	orders, err := c.apiClient.GetActiveOrders(c.accountIndex, marketIndex, auth)
+	if err != nil {
+		return 0, err
+	}
+	// orders which were filled or canceled right away are no longer active
+	inactiveOrders, err := c.apiClient.GetInactiveOrders(c.accountIndex, marketIndex, inactiveOrdersLookupLimit, auth)
+	if err != nil {
+		return 0, err
+	}
+	orders = append(orders, inactiveOrders...)
+
+	for _, order := range orders {
+		if order.ClientOrderIndex == clientOrderIndex {
+			return order.OrderIndex, nil
+		}
+	}
+	return 0, fmt.Errorf("no order found with client order index %v", clientOrderIndex)
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	curve "github.com/elliottech/poseidon_crypto/curve/ecgfp5"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+func newTestTxClient(t *testing.T, apiClient *HTTPClient) *TxClient {
+	t.Helper()
+	privateKey := curve.SampleScalar(nil)
+	txClient, err := NewTxClient(apiClient, hexutil.Encode(privateKey.ToLittleEndianBytes()), 7, 3, 304)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return txClient
+}
+
+func TestDryRunTxIsNeverSent(t *testing.T) {
+	// the endpoint is unreachable, so any real send would fail
+	apiClient := NewHTTPClient("http://127.0.0.1:1")
+	txClient := newTestTxClient(t, apiClient)
+
+	nonce := int64(1)
+	tx, err := txClient.GetCancelAllOrdersTransaction(&types.CancelAllOrdersTxReq{
+		TimeInForce: txtypes.ImmediateCancelAll,
+	}, &types.TransactOpts{Nonce: &nonce, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		result, err := txClient.SendTx(tx)
+		if err != nil {
+			t.Fatalf("send %v: %v", i, err)
+		}
+		if !result.DryRun || result.TxHash != tx.GetTxHash() {
+			t.Fatalf("send %v: expected a simulated send, got %+v", i, result)
+		}
+	}
+
+	if _, err := apiClient.SendRawTx(tx); !errors.Is(err, ErrDryRunTx) {
+		t.Fatalf("expected ErrDryRunTx from SendRawTx, got %v", err)
+	}
+	if _, err := apiClient.SendRawTxBatch([]txtypes.TxInfo{tx}); !errors.Is(err, ErrDryRunTx) {
+		t.Fatalf("expected ErrDryRunTx from SendRawTxBatch, got %v", err)
+	}
+}
+
+// newTxStatusServer accepts every tx with the hash 0xaa, which doesn't match the signed one, and reports status for it
+func newTxStatusServer(t *testing.T, status int64) *HTTPClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/sendTx":
+			w.Write([]byte(`{"code":200,"tx_hash":"0xaa"}`))
+		case "/api/v1/tx":
+			fmt.Fprintf(w, `{"code":200,"hash":"0xaa","status":%v}`, status)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	apiClient := NewHTTPClient(server.URL)
+	apiClient.SetTxHashVerification(true)
+	return apiClient
+}
+
+func TestSendAndWaitTracksTxAcceptedWithError(t *testing.T) {
+	apiClient := newTxStatusServer(t, TxStatusExecuted)
+	txClient := newTestTxClient(t, apiClient)
+	txClient.SetTxTracker(NewTxTracker(apiClient, time.Millisecond))
+
+	nonce := int64(1)
+	tx, err := txClient.GetCancelAllOrdersTransaction(&types.CancelAllOrdersTxReq{
+		TimeInForce: txtypes.ImmediateCancelAll,
+	}, &types.TransactOpts{Nonce: &nonce})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := txClient.SendAndWait(context.Background(), tx)
+	if !errors.Is(err, ErrTxHashMismatch) {
+		t.Fatalf("expected ErrTxHashMismatch, got %v", err)
+	}
+	if result == nil || result.TxHash != "0xaa" || result.State != TxStateExecuted {
+		t.Fatalf("expected the accepted tx to be tracked, got %+v", result)
+	}
+
+	// tracking stops with ctx, leaving the outcome unknown
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	pending := newTxStatusServer(t, TxStatusPending)
+	txClient = newTestTxClient(t, pending)
+	tx, err = txClient.GetCancelAllOrdersTransaction(&types.CancelAllOrdersTxReq{
+		TimeInForce: txtypes.ImmediateCancelAll,
+	}, &types.TransactOpts{Nonce: &nonce})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = txClient.SendAndWait(ctx, tx)
+	if !errors.Is(err, ErrTxHashMismatch) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected ErrTxHashMismatch & context.Canceled, got %v", err)
+	}
+	if result == nil || result.TxHash != "0xaa" || result.State != TxStateUnknown {
+		t.Fatalf("expected an unknown result, got %+v", result)
+	}
+}
+
+func TestWaitExpiresPendingTx(t *testing.T) {
+	tracker := NewTxTracker(newTxStatusServer(t, TxStatusPending), time.Millisecond)
+	expiredAt := time.Now().Add(-txExpiryGrace - time.Second).UnixMilli()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	result, err := tracker.Wait(ctx, "0xaa", expiredAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.State != TxStateExpired || result.Tx == nil {
+		t.Fatalf("expected the pending tx to expire, got %+v", result)
+	}
+}
+
+func TestZeroTxStateIsUnknown(t *testing.T) {
+	if state := (TxResult{}).State; state != TxStateUnknown || state.String() != "unknown" {
+		t.Fatalf("zero TxState is %v", state)
+	}
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultPollInterval = time.Second
+	// give the sequencer some time to index txs which were accepted right before their ExpiredAt
+	txExpiryGrace = 5 * time.Second
+)
+
+type TxState uint8
+
+const (
+	// TxStateUnknown is the state of a tx whose outcome couldn't be determined, e.g. as tracking it failed
+	TxStateUnknown TxState = iota
+	TxStateExecuted
+	TxStateFailed
+	TxStateExpired
+	TxStateDryRun
+)
+
+func (s TxState) String() string {
+	switch s {
+	case TxStateUnknown:
+		return "unknown"
+	case TxStateExecuted:
+		return "executed"
+	case TxStateFailed:
+		return "failed"
+	case TxStateExpired:
+		return "expired"
+	case TxStateDryRun:
+		return "dry run"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(s))
+	}
+}
+
+type TxResult struct {
+	TxHash string
+	State  TxState
+	// Tx is nil if the tx expired without ever being seen by Lighter
+	Tx *Tx
+	// OrderIndex is only set for executed CreateOrder txs which have a ClientOrderIndex
+	OrderIndex int64
+}
+
+// TxTracker polls Lighter for the status of submitted txs
+type TxTracker struct {
+	apiClient    *HTTPClient
+	pollInterval time.Duration
+}
+
+func NewTxTracker(apiClient *HTTPClient, pollInterval time.Duration) *TxTracker {
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	return &TxTracker{
+		apiClient:    apiClient,
+		pollInterval: pollInterval,
+	}
+}
+
+// Wait blocks until the tx is executed or failed. If the tx is neither once expiredAt (unix millis) has passed, whether
+// it's unknown to Lighter or still pending, it can no longer be executed and is reported as expired.
+// Pass 0 for expiredAt to wait until ctx is done.
+func (t *TxTracker) Wait(ctx context.Context, txHash string, expiredAt int64) (*TxResult, error) {
+	if t.apiClient == nil {
+		return nil, fmt.Errorf("HTTPClient is nil, can't track tx status")
+	}
+
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if result := t.poll(txHash, expiredAt); result != nil {
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll looks the tx up once, like a single iteration of Wait. The result is nil while the tx can still be executed.
+func (t *TxTracker) Poll(txHash string, expiredAt int64) (*TxResult, error) {
+	if t.apiClient == nil {
+		return nil, fmt.Errorf("HTTPClient is nil, can't track tx status")
+	}
+	return t.poll(txHash, expiredAt), nil
+}
+
+func (t *TxTracker) poll(txHash string, expiredAt int64) *TxResult {
+	// lookup errors are expected until the tx gets indexed, so they're only relevant once the tx has expired
+	tx, err := t.apiClient.GetTxByHash(txHash)
+	if err == nil {
+		switch tx.Status {
+		case TxStatusExecuted:
+			return &TxResult{TxHash: txHash, State: TxStateExecuted, Tx: tx}
+		case TxStatusFailed:
+			return &TxResult{TxHash: txHash, State: TxStateFailed, Tx: tx}
+		}
+	}
+	if expiredAt != 0 && time.Now().After(time.UnixMilli(expiredAt).Add(txExpiryGrace)) {
+		return &TxResult{TxHash: txHash, State: TxStateExpired, Tx: tx}
+	}
+	return nil
+}
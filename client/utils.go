@@ -0,0 +1,62 @@
+package client
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+// txMeta holds the fields shared by every tx info, read back from its JSON form.
+// Transfer & Withdraw name the account FromAccountIndex, every other tx uses AccountIndex.
+type txMeta struct {
+	AccountIndex     int64
+	FromAccountIndex int64
+	ApiKeyIndex      uint8
+	ExpiredAt        int64
+	Nonce            int64
+}
+
+func (m *txMeta) accountIndex() int64 {
+	if m.FromAccountIndex != 0 {
+		return m.FromAccountIndex
+	}
+	return m.AccountIndex
+}
+
+func parseTxMeta(tx txtypes.TxInfo) (*txMeta, error) {
+	txInfo, err := tx.GetTxInfo()
+	if err != nil {
+		return nil, err
+	}
+	meta := &txMeta{}
+	if err := json.Unmarshal([]byte(txInfo), meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// equalTxHash compares tx hashes regardless of case & 0x prefix, as SignedHash is stored without the prefix
+func equalTxHash(a, b string) bool {
+	return strings.EqualFold(strings.TrimPrefix(a, "0x"), strings.TrimPrefix(b, "0x"))
+}
+
+// parseDecimal parses the decimal strings used by the HTTP API for amounts & prices. Empty strings are read as 0.
+func parseDecimal(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// toDecimal converts an integer amount or price, as used in txs, to its decimal value
+func toDecimal(value int64, decimals uint8) float64 {
+	return float64(value) / math.Pow10(int(decimals))
+}
+
+// toTicks converts a decimal value to the integer amount or price used in txs, rounding to the nearest tick
+func toTicks(value float64, decimals uint8) int64 {
+	return int64(math.Round(value * math.Pow10(int(decimals))))
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/uncle-gua/lighter-go/client"
+	"github.com/uncle-gua/lighter-go/signer"
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+var txTypes = map[string]uint8{
+	"change-pub-key":        txtypes.TxTypeL2ChangePubKey,
+	"create-sub-account":    txtypes.TxTypeL2CreateSubAccount,
+	"create-public-pool":    txtypes.TxTypeL2CreatePublicPool,
+	"update-public-pool":    txtypes.TxTypeL2UpdatePublicPool,
+	"transfer":              txtypes.TxTypeL2Transfer,
+	"withdraw":              txtypes.TxTypeL2Withdraw,
+	"create-order":          txtypes.TxTypeL2CreateOrder,
+	"create-grouped-orders": txtypes.TxTypeL2CreateGroupedOrders,
+	"cancel-order":          txtypes.TxTypeL2CancelOrder,
+	"cancel-all-orders":     txtypes.TxTypeL2CancelAllOrders,
+	"modify-order":          txtypes.TxTypeL2ModifyOrder,
+	"mint-shares":           txtypes.TxTypeL2MintShares,
+	"burn-shares":           txtypes.TxTypeL2BurnShares,
+	"update-leverage":       txtypes.TxTypeL2UpdateLeverage,
+	"update-margin":         txtypes.TxTypeL2UpdateMargin,
+}
+
+// signedTx is the output of sign & the input of send
+type signedTx struct {
+	TxType        uint8           `json:"txType"`
+	TxInfo        json.RawMessage `json:"txInfo"`
+	TxHash        string          `json:"txHash"`
+	MessageToSign string          `json:"messageToSign,omitempty"`
+}
+
+func runKeys(_ *Config, args []string) (any, error) {
+	flags := flag.NewFlagSet("keys", flag.ContinueOnError)
+	seed := flags.String("seed", "", "seed of the key, random by default")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	key := signer.GenerateKeyManager(*seed)
+	pubKey := key.PubKeyBytes()
+	return map[string]string{
+		"privateKey": hexutil.Encode(key.PrvKeyBytes()),
+		"publicKey":  hexutil.Encode(pubKey[:]),
+	}, nil
+}
+
+func runPubKey(config *Config, args []string) (any, error) {
+	flags := flag.NewFlagSet("pubkey", flag.ContinueOnError)
+	privateKey := flags.String("private-key", config.PrivateKey, "private key, defaults to the configured one")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	b, err := hexutil.Decode(ensureHexPrefix(*privateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key. err: %w", err)
+	}
+	keyManager, err := signer.NewKeyManager(b)
+	if err != nil {
+		return nil, err
+	}
+	pubKey := keyManager.PubKeyBytes()
+	return map[string]string{"publicKey": hexutil.Encode(pubKey[:])}, nil
+}
+
+func runAuth(config *Config, args []string) (any, error) {
+	flags := flag.NewFlagSet("auth", flag.ContinueOnError)
+	deadline := flags.Int64("deadline", 0, "unix seconds, at most 8 hours from now. Defaults to 7 hours from now")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	txClient, err := config.txClient()
+	if err != nil {
+		return nil, err
+	}
+	if *deadline == 0 {
+		*deadline = time.Now().Add(time.Hour * 7).Unix()
+	}
+	token, err := txClient.GetAuthToken(time.Unix(*deadline, 0))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"token": token, "deadline": *deadline}, nil
+}
+
+func runSign(config *Config, args []string) (any, error) {
+	flags := flag.NewFlagSet("sign", flag.ContinueOnError)
+	txTypeFlag := flags.String("type", "", "tx type, by name (e.g. create-order) or number")
+	requestFlag := flags.String("json", "", "request as JSON, with the fields of the matching types.*TxReq & the optional Nonce & ExpiredAt. @file reads it from a file, - from stdin")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	txType, err := parseTxType(*txTypeFlag)
+	if err != nil {
+		return nil, err
+	}
+	request, err := readRequest(*requestFlag)
+	if err != nil {
+		return nil, err
+	}
+	if err := addFields(request, flags.Args()); err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	txClient, err := config.txClient()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := txClient.SignTxJSON(txType, b)
+	if err != nil {
+		return nil, err
+	}
+	txInfo, err := tx.GetTxInfo()
+	if err != nil {
+		return nil, err
+	}
+	result := &signedTx{TxType: tx.GetTxType(), TxInfo: json.RawMessage(txInfo), TxHash: tx.GetTxHash()}
+	if l1Tx, ok := tx.(client.L1SignedTx); ok {
+		result.MessageToSign = l1Tx.GetL1SignatureBody()
+	}
+	return result, nil
+}
+
+func runSend(config *Config, args []string) (any, error) {
+	flags := flag.NewFlagSet("send", flag.ContinueOnError)
+	txTypeFlag := flags.String("type", "", "tx type, by name (e.g. create-order) or number")
+	txInfoFlag := flags.String("tx-info", "", "signed tx info as JSON. Without it, the output of sign is read from stdin")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	signed := &signedTx{}
+	if *txInfoFlag != "" {
+		txType, err := parseTxType(*txTypeFlag)
+		if err != nil {
+			return nil, err
+		}
+		signed.TxType = txType
+		signed.TxInfo = json.RawMessage(*txInfoFlag)
+	} else if err := json.NewDecoder(os.Stdin).Decode(signed); err != nil {
+		return nil, fmt.Errorf("failed to read signed tx from stdin. err: %w", err)
+	}
+
+	tx, err := txtypes.ParseTxInfo(signed.TxType, signed.TxInfo)
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := config.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	txHash, err := httpClient.SendRawTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"txHash": txHash}, nil
+}
+
+func runNonce(config *Config, args []string) (any, error) {
+	flags := flag.NewFlagSet("nonce", flag.ContinueOnError)
+	accountIndex := flags.Int64("account", config.AccountIndex, "account index, defaults to the configured one")
+	apiKeyIndex := flags.Uint("api-key", uint(config.ApiKeyIndex), "API key index, defaults to the configured one")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	httpClient, err := config.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := httpClient.GetNextNonce(*accountIndex, uint8(*apiKeyIndex))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]int64{"nonce": nonce}, nil
+}
+
+func runApiKeys(config *Config, args []string) (any, error) {
+	flags := flag.NewFlagSet("apikeys", flag.ContinueOnError)
+	accountIndex := flags.Int64("account", config.AccountIndex, "account index, defaults to the configured one")
+	apiKeyIndex := flags.Uint("api-key", uint(txtypes.NilApiKeyIndex), "API key index, defaults to all of them")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	httpClient, err := config.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	apiKeys, err := httpClient.GetApiKey(*accountIndex, uint8(*apiKeyIndex))
+	if err != nil {
+		return nil, err
+	}
+	return apiKeys.ApiKeys, nil
+}
+
+func runTransferFee(config *Config, args []string) (any, error) {
+	flags := flag.NewFlagSet("transfer-fee", flag.ContinueOnError)
+	toAccountIndex := flags.Int64("to", -1, "account index receiving the transfer")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if *toAccountIndex < 0 {
+		return nil, fmt.Errorf("missing -to")
+	}
+
+	txClient, err := config.txClient()
+	if err != nil {
+		return nil, err
+	}
+	fee, err := txClient.TransferFee(*toAccountIndex)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]int64{"fee": fee}, nil
+}
+
+func runCancelAll(config *Config, args []string) (any, error) {
+	flags := flag.NewFlagSet("cancel-all", flag.ContinueOnError)
+	in := flags.Duration("in", 0, "schedule the cancel all after the duration, instead of cancelling now")
+	abort := flags.Bool("abort", false, "abort the scheduled cancel all")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	req := &types.CancelAllOrdersTxReq{TimeInForce: txtypes.ImmediateCancelAll, Time: txtypes.NilOrderExpiry}
+	switch {
+	case *abort && *in != 0:
+		return nil, fmt.Errorf("-in & -abort can't be used together")
+	case *abort:
+		req.TimeInForce = txtypes.AbortScheduledCancelAll
+	case *in != 0:
+		req.TimeInForce = txtypes.ScheduledCancelAll
+		req.Time = time.Now().Add(*in).UnixMilli()
+	}
+
+	txClient, err := config.txClient()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := txClient.GetCancelAllOrdersTransaction(req, nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := txClient.SendTx(tx)
+	if err != nil {
+		if result != nil && result.TxHash != "" {
+			return nil, &sentTxError{txHash: result.TxHash, err: err}
+		}
+		return nil, err
+	}
+	return map[string]string{"txHash": result.TxHash}, nil
+}
+
+// sentTxError is returned when the tx was accepted, so fail still reports its hash
+type sentTxError struct {
+	txHash string
+	err    error
+}
+
+func (e *sentTxError) Error() string {
+	return e.err.Error()
+}
+
+func (e *sentTxError) Unwrap() error {
+	return e.err
+}
+
+func parseTxType(s string) (uint8, error) {
+	if txType, ok := txTypes[s]; ok {
+		return txType, nil
+	}
+	txType, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid tx type %q", s)
+	}
+	return uint8(txType), nil
+}
+
+// readRequest reads the JSON request given directly, from a file with @file or from stdin with -
+func readRequest(s string) (map[string]any, error) {
+	var b []byte
+	var err error
+	switch {
+	case s == "":
+		return map[string]any{}, nil
+	case s == "-":
+		b, err = io.ReadAll(os.Stdin)
+	case strings.HasPrefix(s, "@"):
+		b, err = os.ReadFile(s[1:])
+	default:
+		b = []byte(s)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	request := map[string]any{}
+	if err := decodeJSON(b, &request); err != nil {
+		return nil, fmt.Errorf("failed to parse request. err: %w", err)
+	}
+	return request, nil
+}
+
+// addFields adds the Field=value arguments to the request, with the value read as JSON when possible
+func addFields(request map[string]any, args []string) error {
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Errorf("invalid field %s, expected Field=value", arg)
+		}
+		var v any
+		if err := decodeJSON([]byte(value), &v); err != nil {
+			v = value
+		}
+		request[name] = v
+	}
+	return nil
+}
+
+// decodeJSON keeps numbers as json.Number, so large integers aren't rounded through float64
+func decodeJSON(b []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return fmt.Errorf("unexpected data after the JSON value")
+	}
+	return nil
+}
+
+func ensureHexPrefix(s string) string {
+	if !strings.HasPrefix(s, "0x") {
+		return "0x" + s
+	}
+	return s
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/uncle-gua/lighter-go/signer"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+func newTestConfig(url string) *Config {
+	return &Config{
+		URL:          url,
+		PrivateKey:   hexutil.Encode(signer.GenerateKeyManager("cmd test").PrvKeyBytes()),
+		ChainId:      304,
+		AccountIndex: 7,
+		ApiKeyIndex:  3,
+	}
+}
+
+func TestParseTxType(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected uint8
+		err      bool
+	}{
+		{s: "create-order", expected: txtypes.TxTypeL2CreateOrder},
+		{s: "change-pub-key", expected: txtypes.TxTypeL2ChangePubKey},
+		{s: "14", expected: txtypes.TxTypeL2CreateOrder},
+		{s: "255", expected: 255},
+		{s: "256", err: true},
+		{s: "-1", err: true},
+		{s: "", err: true},
+		{s: "CreateOrder", err: true},
+	}
+	for _, tt := range tests {
+		txType, err := parseTxType(tt.s)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("%q: expected an error", tt.s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: %v", tt.s, err)
+		}
+		if txType != tt.expected {
+			t.Fatalf("%q: expected %v, got %v", tt.s, tt.expected, txType)
+		}
+	}
+}
+
+func TestReadRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "request.json")
+	if err := os.WriteFile(path, []byte(`{"MarketIndex":2}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	stdin, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stdin.WriteString(`{"MarketIndex":3}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stdin.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer func(s *os.File) { os.Stdin = s }(os.Stdin)
+	os.Stdin = stdin
+
+	tests := []struct {
+		name     string
+		s        string
+		expected map[string]any
+		err      bool
+	}{
+		{name: "empty", s: "", expected: map[string]any{}},
+		{name: "inline", s: `{"MarketIndex":1}`, expected: map[string]any{"MarketIndex": json.Number("1")}},
+		{name: "file", s: "@" + path, expected: map[string]any{"MarketIndex": json.Number("2")}},
+		{name: "stdin", s: "-", expected: map[string]any{"MarketIndex": json.Number("3")}},
+		// large integers aren't rounded through float64
+		{name: "large integer", s: `{"Price":9007199254740993}`, expected: map[string]any{"Price": json.Number("9007199254740993")}},
+		{name: "missing file", s: "@" + filepath.Join(t.TempDir(), "missing.json"), err: true},
+		{name: "invalid JSON", s: `{"MarketIndex":`, err: true},
+		{name: "not an object", s: `[1]`, err: true},
+		{name: "trailing data", s: `{} {}`, err: true},
+	}
+	for _, tt := range tests {
+		request, err := readRequest(tt.s)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("%s: expected an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(request, tt.expected) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.expected, request)
+		}
+	}
+}
+
+func TestAddFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  map[string]any
+		args     []string
+		expected map[string]any
+		err      bool
+	}{
+		{name: "none", request: map[string]any{}, expected: map[string]any{}},
+		{
+			name:     "JSON values",
+			request:  map[string]any{},
+			args:     []string{"MarketIndex=1", "IsAsk=true", `Orders=[{"Price":2}]`},
+			expected: map[string]any{"MarketIndex": json.Number("1"), "IsAsk": true, "Orders": []any{map[string]any{"Price": json.Number("2")}}},
+		},
+		// values which aren't JSON are kept as strings, e.g. hex
+		{name: "string", request: map[string]any{}, args: []string{"Memo=0x01"}, expected: map[string]any{"Memo": "0x01"}},
+		{name: "quoted string", request: map[string]any{}, args: []string{`Memo="1"`}, expected: map[string]any{"Memo": "1"}},
+		{name: "empty value", request: map[string]any{}, args: []string{"Memo="}, expected: map[string]any{"Memo": ""}},
+		// only the first = separates the name
+		{name: "value with =", request: map[string]any{}, args: []string{"Memo=a=b"}, expected: map[string]any{"Memo": "a=b"}},
+		{
+			name:     "overrides the request",
+			request:  map[string]any{"MarketIndex": json.Number("1"), "Price": json.Number("5")},
+			args:     []string{"MarketIndex=2"},
+			expected: map[string]any{"MarketIndex": json.Number("2"), "Price": json.Number("5")},
+		},
+		{name: "missing =", request: map[string]any{}, args: []string{"MarketIndex"}, err: true},
+	}
+	for _, tt := range tests {
+		err := addFields(tt.request, tt.args)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("%s: expected an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(tt.request, tt.expected) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.expected, tt.request)
+		}
+	}
+}
+
+func TestRunSign(t *testing.T) {
+	// the nonce is explicit, so no url is needed
+	config := newTestConfig("")
+	result, err := runSign(config, []string{
+		"-type", "create-order",
+		"-json", `{"MarketIndex":1,"BaseAmount":10,"Price":100,"Nonce":5,"ExpiredAt":1900000000000}`,
+		"Price=200", "ClientOrderIndex=9",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := result.(*signedTx)
+	txInfo := &struct {
+		AccountIndex     int64
+		ApiKeyIndex      uint8
+		MarketIndex      int16
+		ClientOrderIndex int64
+		BaseAmount       int64
+		Price            uint32
+		Nonce            int64
+		ExpiredAt        int64
+	}{}
+	if err := json.Unmarshal(signed.TxInfo, txInfo); err != nil {
+		t.Fatal(err)
+	}
+	if signed.TxType != txtypes.TxTypeL2CreateOrder || signed.TxHash == "" {
+		t.Fatalf("unexpected signed tx %+v", signed)
+	}
+	if txInfo.AccountIndex != 7 || txInfo.ApiKeyIndex != 3 || txInfo.MarketIndex != 1 || txInfo.ClientOrderIndex != 9 ||
+		txInfo.BaseAmount != 10 || txInfo.Price != 200 || txInfo.Nonce != 5 || txInfo.ExpiredAt != 1900000000000 {
+		t.Fatalf("unexpected tx info %+v", txInfo)
+	}
+
+	if _, err := runSign(config, []string{"-type", "create-order", "Price"}); err == nil {
+		t.Fatal("expected an invalid field to fail")
+	}
+	if _, err := runSign(config, []string{"-type", "unknown"}); err == nil {
+		t.Fatal("expected an unknown tx type to fail")
+	}
+}
+
+func TestRunCancelAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/nextNonce":
+			w.Write([]byte(`{"code":200,"nonce":1}`))
+		case "/api/v1/sendTx":
+			fmt.Fprintf(w, `{"code":200,"tx_hash":"0xaa"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	result, err := runCancelAll(newTestConfig(server.URL), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, map[string]string{"txHash": "0xaa"}) {
+		t.Fatalf("unexpected result %v", result)
+	}
+	if _, err := runCancelAll(newTestConfig(server.URL), []string{"-in", "1m", "-abort"}); err == nil {
+		t.Fatal("expected -in & -abort to fail")
+	}
+}
+
+func TestErrorOutput(t *testing.T) {
+	err := fmt.Errorf("cancel all failed. err: %w", &sentTxError{txHash: "0xaa", err: fmt.Errorf("journal is full")})
+	if output := errorOutput(err); !reflect.DeepEqual(output, map[string]string{"error": err.Error(), "txHash": "0xaa"}) {
+		t.Fatalf("unexpected output %v", output)
+	}
+	if output := errorOutput(fmt.Errorf("not sent")); !reflect.DeepEqual(output, map[string]string{"error": "not sent"}) {
+		t.Fatalf("unexpected output %v", output)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/uncle-gua/lighter-go/client"
+)
+
+// Config is read from the JSON file given by -config or LIGHTER_CONFIG, then overridden by the LIGHTER_* env variables
+type Config struct {
+	URL          string `json:"url"`
+	PrivateKey   string `json:"privateKey"`
+	ChainId      uint32 `json:"chainId"`
+	AccountIndex int64  `json:"accountIndex"`
+	ApiKeyIndex  uint8  `json:"apiKeyIndex"`
+}
+
+func loadConfig(path string) (*Config, error) {
+	config := &Config{}
+	if path == "" {
+		path = os.Getenv("LIGHTER_CONFIG")
+	}
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, config); err != nil {
+			return nil, fmt.Errorf("failed to parse config %s. err: %w", path, err)
+		}
+	}
+
+	if url := os.Getenv("LIGHTER_URL"); url != "" {
+		config.URL = url
+	}
+	if privateKey := os.Getenv("LIGHTER_PRIVATE_KEY"); privateKey != "" {
+		config.PrivateKey = privateKey
+	}
+	if err := envUint("LIGHTER_CHAIN_ID", 32, func(v uint64) { config.ChainId = uint32(v) }); err != nil {
+		return nil, err
+	}
+	if err := envUint("LIGHTER_ACCOUNT_INDEX", 63, func(v uint64) { config.AccountIndex = int64(v) }); err != nil {
+		return nil, err
+	}
+	if err := envUint("LIGHTER_API_KEY_INDEX", 8, func(v uint64) { config.ApiKeyIndex = uint8(v) }); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func envUint(name string, bitSize int, set func(uint64)) error {
+	s := os.Getenv(name)
+	if s == "" {
+		return nil
+	}
+	v, err := strconv.ParseUint(s, 10, bitSize)
+	if err != nil {
+		return fmt.Errorf("invalid %s. err: %w", name, err)
+	}
+	set(v)
+	return nil
+}
+
+func (c *Config) httpClient() (*client.HTTPClient, error) {
+	if c.URL == "" {
+		return nil, fmt.Errorf("missing url, set it in the config or LIGHTER_URL")
+	}
+	return client.NewHTTPClient(c.URL), nil
+}
+
+// txClient returns the client of the configured API key. Without a url, nonces can't be fetched and txs can't be sent.
+func (c *Config) txClient() (*client.TxClient, error) {
+	if c.PrivateKey == "" {
+		return nil, fmt.Errorf("missing privateKey, set it in the config or LIGHTER_PRIVATE_KEY")
+	}
+	if c.AccountIndex <= 0 {
+		return nil, fmt.Errorf("missing accountIndex, set it in the config or LIGHTER_ACCOUNT_INDEX")
+	}
+	if c.ChainId == 0 {
+		return nil, fmt.Errorf("missing chainId, set it in the config or LIGHTER_CHAIN_ID")
+	}
+	return client.NewTxClient(client.NewHTTPClient(c.URL), c.PrivateKey, c.AccountIndex, c.ApiKeyIndex, c.ChainId)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"url":"https://file","privateKey":"0x01","chainId":304,"accountIndex":7,"apiKeyIndex":3}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	fromFile := Config{URL: "https://file", PrivateKey: "0x01", ChainId: 304, AccountIndex: 7, ApiKeyIndex: 3}
+	tests := []struct {
+		name     string
+		path     string
+		env      map[string]string
+		expected Config
+		err      bool
+	}{
+		{name: "file", path: path, expected: fromFile},
+		{name: "file from env", env: map[string]string{"LIGHTER_CONFIG": path}, expected: fromFile},
+		{name: "no file", expected: Config{}},
+		{
+			name: "env overrides file",
+			path: path,
+			env: map[string]string{
+				"LIGHTER_URL":           "https://env",
+				"LIGHTER_PRIVATE_KEY":   "0x02",
+				"LIGHTER_CHAIN_ID":      "1",
+				"LIGHTER_ACCOUNT_INDEX": "8",
+				"LIGHTER_API_KEY_INDEX": "4",
+			},
+			expected: Config{URL: "https://env", PrivateKey: "0x02", ChainId: 1, AccountIndex: 8, ApiKeyIndex: 4},
+		},
+		{
+			name:     "partial env",
+			path:     path,
+			env:      map[string]string{"LIGHTER_ACCOUNT_INDEX": "9"},
+			expected: Config{URL: "https://file", PrivateKey: "0x01", ChainId: 304, AccountIndex: 9, ApiKeyIndex: 3},
+		},
+		{name: "invalid chain id", env: map[string]string{"LIGHTER_CHAIN_ID": "abc"}, err: true},
+		{name: "api key index out of range", env: map[string]string{"LIGHTER_API_KEY_INDEX": "256"}, err: true},
+		{name: "negative account index", env: map[string]string{"LIGHTER_ACCOUNT_INDEX": "-1"}, err: true},
+		{name: "missing file", path: filepath.Join(t.TempDir(), "missing.json"), err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"LIGHTER_CONFIG", "LIGHTER_URL", "LIGHTER_PRIVATE_KEY", "LIGHTER_CHAIN_ID", "LIGHTER_ACCOUNT_INDEX", "LIGHTER_API_KEY_INDEX"} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			config, err := loadConfig(tt.path)
+			if tt.err {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *config != tt.expected {
+				t.Fatalf("expected %+v, got %+v", tt.expected, *config)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// lighter is a command line tool to manage API keys, sign & send txs and query the account of an API key.
+// Results are written to stdout as JSON, errors to stderr with a non-zero exit code.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+type command struct {
+	usage string
+	run   func(config *Config, args []string) (any, error)
+}
+
+var commands = map[string]*command{
+	"keys":         {"keys [-seed seed]: generate an API key", runKeys},
+	"pubkey":       {"pubkey [-private-key key]: derive the public key of the configured, or given, private key", runPubKey},
+	"auth":         {"auth [-deadline unix]: create an auth token, valid for 7 hours by default", runAuth},
+	"sign":         {"sign -type type [-json request|@file|-] [Field=value ...]: sign any tx type, fetching the nonce if it's not given", runSign},
+	"send":         {"send [-type type -tx-info json]: send a signed tx, read from the output of sign on stdin by default", runSend},
+	"nonce":        {"nonce [-account index] [-api-key index]: get the next nonce of the API key", runNonce},
+	"apikeys":      {"apikeys [-account index] [-api-key index]: get the API keys of the account, all of them by default", runApiKeys},
+	"transfer-fee": {"transfer-fee -to index: get the fee of a transfer to the account", runTransferFee},
+	"cancel-all":   {"cancel-all [-in duration | -abort]: cancel all orders now, schedule it, or abort the scheduled one", runCancelAll},
+}
+
+func main() {
+	flags := flag.NewFlagSet("lighter", flag.ExitOnError)
+	configPath := flags.String("config", "", "JSON config file, defaults to LIGHTER_CONFIG")
+	flags.Usage = usage
+	_ = flags.Parse(os.Args[1:])
+
+	if flags.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %s\n", flags.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		fail(err)
+	}
+	result, err := cmd.run(config, flags.Args()[1:])
+	if err != nil {
+		fail(err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		fail(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: lighter [-config file] command [flags]\n\ncommands:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %s\n", commands[name].usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nconfig: url, privateKey, chainId, accountIndex & apiKeyIndex, overridden by LIGHTER_URL, LIGHTER_PRIVATE_KEY, LIGHTER_CHAIN_ID, LIGHTER_ACCOUNT_INDEX & LIGHTER_API_KEY_INDEX\n")
+}
+
+func fail(err error) {
+	b, _ := json.Marshal(errorOutput(err))
+	fmt.Fprintln(os.Stderr, string(b))
+	os.Exit(1)
+}
+
+// errorOutput is the JSON written to stderr on errors, with the hash of the tx when it was sent anyway
+func errorOutput(err error) map[string]string {
+	ret := map[string]string{"error": err.Error()}
+	var sentErr *sentTxError
+	if errors.As(err, &sentErr) {
+		ret["txHash"] = sentErr.txHash
+	}
+	return ret
+}
@@ -0,0 +1,563 @@
+// bindgen generates the C header, the Python (ctypes) module and the TypeScript (koffi) wrapper of the shared library
+// from the description of its exports in sharedlib/exports.json.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"unicode"
+)
+
+type Spec struct {
+	Doc       string            `json:"doc"`
+	Types     map[string]string `json:"types"`
+	Constants []*Constant       `json:"constants"`
+	Structs   []*Struct         `json:"structs"`
+	Functions []*Function       `json:"functions"`
+}
+
+type Constant struct {
+	Name  string `json:"name"`
+	Value int64  `json:"value"`
+	Doc   string `json:"doc"`
+}
+
+type Field struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Doc  string `json:"doc"`
+}
+
+type Struct struct {
+	Name   string   `json:"name"`
+	Free   string   `json:"free"`
+	Fields []*Field `json:"fields"`
+}
+
+type Function struct {
+	Name    string   `json:"name"`
+	Args    []*Field `json:"args"`
+	Returns string   `json:"returns"`
+	Group   string   `json:"group"`
+	Doc     string   `json:"doc"`
+}
+
+const generatedNotice = "Code generated by sharedlib/bindgen from sharedlib/exports.json. DO NOT EDIT."
+
+func main() {
+	specPath := flag.String("spec", "exports.json", "description of the exports")
+	headerPath := flag.String("header", "", "C header output")
+	pythonPath := flag.String("python", "", "Python module output")
+	typescriptPath := flag.String("typescript", "", "TypeScript module output")
+	flag.Parse()
+
+	b, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	spec := &Spec{}
+	if err := json.Unmarshal(b, spec); err != nil {
+		log.Fatalf("failed to parse %s. err: %v", *specPath, err)
+	}
+	if err := spec.validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	outputs := []struct {
+		path     string
+		generate func(*Spec) string
+	}{
+		{*headerPath, generateHeader},
+		{*pythonPath, generatePython},
+		{*typescriptPath, generateTypeScript},
+	}
+	for _, output := range outputs {
+		if output.path == "" {
+			continue
+		}
+		if err := os.WriteFile(output.path, []byte(output.generate(spec)), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (s *Spec) validate() error {
+	structs := s.structs()
+	for _, fn := range s.Functions {
+		if _, ok := structs[fn.Returns]; !ok && s.Types[fn.Returns] == "" {
+			return fmt.Errorf("unknown return type %s of %s", fn.Returns, fn.Name)
+		}
+		for _, arg := range fn.Args {
+			if _, ok := structs[arg.Type]; !ok && s.Types[arg.Type] == "" {
+				return fmt.Errorf("unknown type %s of %s.%s", arg.Type, fn.Name, arg.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Spec) structs() map[string]*Struct {
+	structs := make(map[string]*Struct, len(s.Structs))
+	for _, st := range s.Structs {
+		structs[st.Name] = st
+	}
+	return structs
+}
+
+// isFree reports whether the function releases memory, which the Python & TypeScript wrappers do on their own
+func (s *Spec) isFree(fn *Function) bool {
+	if fn.Name == "FreeString" {
+		return true
+	}
+	for _, st := range s.Structs {
+		if st.Free == fn.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// resultFields are the fields of a returned struct handed to the caller, leaving out the error
+func resultFields(st *Struct) []*Field {
+	fields := make([]*Field, 0, len(st.Fields))
+	for _, field := range st.Fields {
+		if field.Name != "err" && field.Name != "errCode" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+func hasField(st *Struct, name string) bool {
+	for _, field := range st.Fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func comment(prefix, doc string) string {
+	if doc == "" {
+		return ""
+	}
+	sb := strings.Builder{}
+	for _, line := range strings.Split(doc, "\n") {
+		sb.WriteString(strings.TrimRight(prefix+" "+line, " ") + "\n")
+	}
+	return sb.String()
+}
+
+func snakeCase(s string) string {
+	sb := strings.Builder{}
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// split before an upper case letter following a lower case one, or starting a word after an acronym
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func camelCase(s string) string {
+	runes := []rune(s)
+	// lower the leading acronym, e.g. GenerateAPIKey stays generateAPIKey but APIKey becomes apiKey
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+var cTypes = map[string]string{
+	"string": "char*",
+	"ptr":    "char*",
+	"error":  "char*",
+	"int32":  "int",
+	"int64":  "long long",
+	"void":   "void",
+}
+
+func cType(t string) string {
+	if ct, ok := cTypes[t]; ok {
+		return ct
+	}
+	return t
+}
+
+func generateHeader(s *Spec) string {
+	sb := &strings.Builder{}
+	sb.WriteString(comment("//", generatedNotice))
+	sb.WriteString("//\n")
+	sb.WriteString(comment("//", s.Doc))
+	sb.WriteString("\n#ifndef LIGHTER_SIGNER_H\n#define LIGHTER_SIGNER_H\n")
+
+	for _, st := range s.Structs {
+		if st.Name == "LighterResult" {
+			for _, c := range s.Constants {
+				if c.Doc != "" {
+					sb.WriteString("\n" + comment("//", c.Doc))
+				}
+				fmt.Fprintf(sb, "#define %s %d\n", c.Name, c.Value)
+			}
+		}
+		sb.WriteString("\ntypedef struct {\n")
+		for _, field := range st.Fields {
+			sb.WriteString(comment("\t//", field.Doc))
+			fmt.Fprintf(sb, "\t%s %s;\n", cType(field.Type), field.Name)
+		}
+		fmt.Fprintf(sb, "} %s;\n", st.Name)
+	}
+
+	group := ""
+	for _, fn := range s.Functions {
+		if fn.Group != group {
+			group = fn.Group
+			fmt.Fprintf(sb, "\n// %s\n", group)
+		}
+		sb.WriteString(comment("//", fn.Doc))
+		args := make([]string, 0, len(fn.Args))
+		for _, arg := range fn.Args {
+			args = append(args, cType(arg.Type)+" "+arg.Name)
+		}
+		if len(args) == 0 {
+			args = append(args, "void")
+		}
+		fmt.Fprintf(sb, "%s %s(%s);\n", cType(fn.Returns), fn.Name, strings.Join(args, ", "))
+	}
+	sb.WriteString("\n#endif\n")
+	return sb.String()
+}
+
+var (
+	pythonCTypes = map[string]string{
+		"string": "ctypes.c_char_p",
+		"ptr":    "ctypes.c_void_p",
+		"error":  "ctypes.c_void_p",
+		"int32":  "ctypes.c_int",
+		"int64":  "ctypes.c_longlong",
+		"void":   "None",
+	}
+	pythonTypes = map[string]string{
+		"string": "str",
+		"int32":  "int",
+		"int64":  "int",
+		"error":  "None",
+		"void":   "None",
+	}
+)
+
+func generatePython(s *Spec) string {
+	structs := s.structs()
+	sb := &strings.Builder{}
+	sb.WriteString(comment("#", generatedNotice))
+	sb.WriteString(`"""Typed ctypes bindings of the lighter-go signer shared library.
+
+Strings returned by the library are copied & freed by the wrappers, and errors are raised as LighterError.
+"""
+
+import ctypes
+from typing import Optional
+
+`)
+	for _, c := range s.Constants {
+		fmt.Fprintf(sb, "%s = %d\n", c.Name, c.Value)
+	}
+	sb.WriteString(`
+
+class LighterError(Exception):
+    def __init__(self, message: str, code: int = 0):
+        super().__init__(message)
+        self.code = code
+`)
+
+	for _, st := range s.Structs {
+		fmt.Fprintf(sb, "\n\nclass %s(ctypes.Structure):\n    _fields_ = [\n", st.Name)
+		for _, field := range st.Fields {
+			t := pythonCTypes[field.Type]
+			if field.Type == "string" {
+				// kept as raw pointers, so they can be freed after being copied
+				t = "ctypes.c_void_p"
+			}
+			fmt.Fprintf(sb, "        (%q, %s),\n", field.Name, t)
+		}
+		sb.WriteString("    ]\n")
+	}
+
+	sb.WriteString(`
+
+class Signer:
+    def __init__(self, path: str):
+        self._lib = ctypes.CDLL(path)
+`)
+	for _, fn := range s.Functions {
+		argTypes := make([]string, 0, len(fn.Args))
+		for _, arg := range fn.Args {
+			t, ok := pythonCTypes[arg.Type]
+			if !ok {
+				t = arg.Type
+			}
+			argTypes = append(argTypes, t)
+		}
+		restype, ok := pythonCTypes[fn.Returns]
+		if !ok {
+			restype = fn.Returns
+		}
+		fmt.Fprintf(sb, "        self._lib.%s.argtypes = [%s]\n", fn.Name, strings.Join(argTypes, ", "))
+		fmt.Fprintf(sb, "        self._lib.%s.restype = %s\n", fn.Name, restype)
+	}
+
+	sb.WriteString(`
+    def _string(self, ptr: Optional[int]) -> Optional[str]:
+        if not ptr:
+            return None
+        return ctypes.string_at(ptr).decode()
+
+    def _check(self, ptr: Optional[int]) -> None:
+        if ptr:
+            message = self._string(ptr)
+            self._lib.FreeString(ptr)
+            raise LighterError(message)
+`)
+
+	for _, fn := range s.Functions {
+		if s.isFree(fn) {
+			continue
+		}
+		params := []string{"self"}
+		callArgs := make([]string, 0, len(fn.Args))
+		for _, arg := range fn.Args {
+			params = append(params, fmt.Sprintf("%s: %s", snakeCase(arg.Name), pythonTypes[arg.Type]))
+			if arg.Type == "string" {
+				callArgs = append(callArgs, snakeCase(arg.Name)+".encode()")
+			} else {
+				callArgs = append(callArgs, snakeCase(arg.Name))
+			}
+		}
+		call := fmt.Sprintf("self._lib.%s(%s)", fn.Name, strings.Join(callArgs, ", "))
+
+		st, isStruct := structs[fn.Returns]
+		returnType := pythonTypes[fn.Returns]
+		if isStruct {
+			fields := resultFields(st)
+			if len(fields) == 1 {
+				returnType = pythonTypes[fields[0].Type]
+			} else {
+				returnType = "dict"
+			}
+		}
+
+		fmt.Fprintf(sb, "\n    def %s(%s) -> %s:\n", snakeCase(fn.Name), strings.Join(params, ", "), returnType)
+		if fn.Doc != "" {
+			fmt.Fprintf(sb, "        %q\n", fn.Doc)
+		}
+		switch {
+		case fn.Returns == "error":
+			fmt.Fprintf(sb, "        self._check(%s)\n", call)
+		case !isStruct:
+			fmt.Fprintf(sb, "        return %s\n", call)
+		default:
+			code := "0"
+			if hasField(st, "errCode") {
+				code = "result.errCode"
+			}
+			fmt.Fprintf(sb, "        result = %s\n        try:\n", call)
+			fmt.Fprintf(sb, "            if result.err:\n                raise LighterError(self._string(result.err), %s)\n", code)
+			fields := resultFields(st)
+			values := make([]string, 0, len(fields))
+			for _, field := range fields {
+				value := "result." + field.Name
+				if field.Type == "string" {
+					value = fmt.Sprintf("self._string(result.%s)", field.Name)
+				}
+				values = append(values, fmt.Sprintf("%q: %s", field.Name, value))
+			}
+			if len(fields) == 1 {
+				fmt.Fprintf(sb, "            return %s\n", strings.SplitN(values[0], ": ", 2)[1])
+			} else {
+				fmt.Fprintf(sb, "            return {%s}\n", strings.Join(values, ", "))
+			}
+			fmt.Fprintf(sb, "        finally:\n            self._lib.%s(result)\n", st.Free)
+		}
+	}
+	return sb.String()
+}
+
+var (
+	koffiTypes = map[string]string{
+		"string": `"str"`,
+		"ptr":    `"void *"`,
+		"error":  `"void *"`,
+		"int32":  `"int"`,
+		"int64":  `"int64"`,
+		"void":   `"void"`,
+	}
+	typescriptTypes = map[string]string{
+		"string": "string",
+		"int32":  "number",
+		"int64":  "number",
+		"error":  "void",
+		"void":   "void",
+	}
+)
+
+func generateTypeScript(s *Spec) string {
+	structs := s.structs()
+	sb := &strings.Builder{}
+	sb.WriteString(comment("//", generatedNotice))
+	sb.WriteString("//\n// Typed koffi bindings of the lighter-go signer shared library.\n")
+	sb.WriteString("// Strings returned by the library are copied & freed by the wrappers, and errors are thrown as LighterError.\n\n")
+	sb.WriteString("import koffi from \"koffi\";\n\n")
+	for _, c := range s.Constants {
+		fmt.Fprintf(sb, "export const %s = %d;\n", c.Name, c.Value)
+	}
+	sb.WriteString(`
+export class LighterError extends Error {
+  constructor(
+    message: string,
+    public readonly code: number = 0,
+  ) {
+    super(message);
+  }
+}
+`)
+
+	for _, st := range s.Structs {
+		fields := make([]string, 0, len(st.Fields))
+		for _, field := range st.Fields {
+			t := koffiTypes[field.Type]
+			if field.Type == "string" {
+				// kept as raw pointers, so they can be freed after being copied
+				t = `"void *"`
+			}
+			fields = append(fields, fmt.Sprintf("%s: %s", field.Name, t))
+		}
+		fmt.Fprintf(sb, "\nconst %s = koffi.struct(%q, { %s });\n", st.Name, st.Name, strings.Join(fields, ", "))
+
+		if resultFields := resultFields(st); len(resultFields) > 1 {
+			fmt.Fprintf(sb, "\nexport interface %sValue {\n", st.Name)
+			for _, field := range resultFields {
+				t := typescriptTypes[field.Type]
+				if field.Type == "string" {
+					t = "string | null"
+				}
+				fmt.Fprintf(sb, "  %s: %s;\n", field.Name, t)
+			}
+			sb.WriteString("}\n")
+		}
+	}
+
+	sb.WriteString(`
+export class LighterSigner {
+  private readonly fns: Record<string, koffi.KoffiFunction>;
+
+  constructor(path: string) {
+    const lib = koffi.load(path);
+    this.fns = {
+`)
+	for _, fn := range s.Functions {
+		argTypes := make([]string, 0, len(fn.Args))
+		for _, arg := range fn.Args {
+			t, ok := koffiTypes[arg.Type]
+			if !ok {
+				t = arg.Type
+			}
+			argTypes = append(argTypes, t)
+		}
+		ret, ok := koffiTypes[fn.Returns]
+		if !ok {
+			ret = fn.Returns
+		}
+		fmt.Fprintf(sb, "      %s: lib.func(%q, %s, [%s]),\n", fn.Name, fn.Name, ret, strings.Join(argTypes, ", "))
+	}
+	sb.WriteString(`    };
+  }
+
+  private str(ptr: unknown): string | null {
+    return ptr === null ? null : (koffi.decode(ptr, "char", -1) as string);
+  }
+
+  private check(ptr: unknown): void {
+    if (ptr !== null) {
+      const message = this.str(ptr) as string;
+      this.fns.FreeString(ptr);
+      throw new LighterError(message);
+    }
+  }
+`)
+
+	for _, fn := range s.Functions {
+		if s.isFree(fn) {
+			continue
+		}
+		params := make([]string, 0, len(fn.Args))
+		callArgs := make([]string, 0, len(fn.Args))
+		for _, arg := range fn.Args {
+			params = append(params, fmt.Sprintf("%s: %s", arg.Name, typescriptTypes[arg.Type]))
+			callArgs = append(callArgs, arg.Name)
+		}
+		call := fmt.Sprintf("this.fns.%s(%s)", fn.Name, strings.Join(callArgs, ", "))
+
+		st, isStruct := structs[fn.Returns]
+		returnType := typescriptTypes[fn.Returns]
+		if isStruct {
+			fields := resultFields(st)
+			if len(fields) == 1 {
+				returnType = typescriptTypes[fields[0].Type]
+			} else {
+				returnType = st.Name + "Value"
+			}
+		}
+
+		sb.WriteString("\n")
+		if fn.Doc != "" {
+			fmt.Fprintf(sb, "  /** %s */\n", fn.Doc)
+		}
+		fmt.Fprintf(sb, "  %s(%s): %s {\n", camelCase(fn.Name), strings.Join(params, ", "), returnType)
+		switch {
+		case fn.Returns == "error":
+			fmt.Fprintf(sb, "    this.check(%s);\n", call)
+		case !isStruct:
+			fmt.Fprintf(sb, "    return %s;\n", call)
+		default:
+			code := "0"
+			if hasField(st, "errCode") {
+				code = "result.errCode"
+			}
+			fmt.Fprintf(sb, "    const result = %s;\n    try {\n", call)
+			fmt.Fprintf(sb, "      if (result.err !== null) {\n        throw new LighterError(this.str(result.err) as string, %s);\n      }\n", code)
+			fields := resultFields(st)
+			values := make([]string, 0, len(fields))
+			for _, field := range fields {
+				value := "result." + field.Name
+				if field.Type == "string" {
+					value = fmt.Sprintf("this.str(result.%s)", field.Name)
+					if len(fields) == 1 {
+						value += " as string"
+					}
+				}
+				values = append(values, fmt.Sprintf("%s: %s", field.Name, value))
+			}
+			if len(fields) == 1 {
+				fmt.Fprintf(sb, "      return %s;\n", strings.SplitN(values[0], ": ", 2)[1])
+			} else {
+				fmt.Fprintf(sb, "      return { %s };\n", strings.Join(values, ", "))
+			}
+			fmt.Fprintf(sb, "    } finally {\n      this.fns.%s(result);\n    }\n", st.Free)
+		}
+		sb.WriteString("  }\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readSpec(t *testing.T) *Spec {
+	t.Helper()
+	b, err := os.ReadFile("../exports.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := &Spec{}
+	if err := json.Unmarshal(b, spec); err != nil {
+		t.Fatal(err)
+	}
+	if err := spec.validate(); err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+// exportedFunc is a function of the shared library marked with //export, with the spec types of its args & result
+type exportedFunc struct {
+	args    []string
+	returns string
+}
+
+// specTypes are the spec types a cgo type can stand for. A char* argument is either a string or a ptr given back to be freed,
+// and a returned char* is either an error or a ptr.
+func specTypes(expr ast.Expr, result bool) []string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		if sel, ok := t.X.(*ast.SelectorExpr); ok && sel.Sel.Name == "char" {
+			if result {
+				return []string{"error", "ptr"}
+			}
+			return []string{"string", "ptr"}
+		}
+	case *ast.SelectorExpr:
+		switch t.Sel.Name {
+		case "int":
+			return []string{"int32"}
+		case "longlong":
+			return []string{"int64"}
+		default:
+			return []string{t.Sel.Name}
+		}
+	}
+	return nil
+}
+
+func contains(types []string, t string) bool {
+	for _, typ := range types {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
+
+// parseExports finds the //export functions of the shared library sources
+func parseExports(t *testing.T) map[string]*ast.FuncDecl {
+	t.Helper()
+	paths, err := filepath.Glob("../*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	exports := make(map[string]*ast.FuncDecl)
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Doc == nil {
+				continue
+			}
+			for _, c := range fn.Doc.List {
+				if name, ok := strings.CutPrefix(c.Text, "//export "); ok {
+					exports[strings.TrimSpace(name)] = fn
+				}
+			}
+		}
+	}
+	return exports
+}
+
+// TestSpecMatchesExports fails when exports.json drifts from the //export functions, so the generated bindings can't call
+// a function which doesn't exist or with the wrong arguments
+func TestSpecMatchesExports(t *testing.T) {
+	spec := readSpec(t)
+	exports := parseExports(t)
+	if len(exports) == 0 {
+		t.Fatal("no //export function found")
+	}
+
+	described := make(map[string]bool, len(spec.Functions))
+	for _, fn := range spec.Functions {
+		described[fn.Name] = true
+		decl, ok := exports[fn.Name]
+		if !ok {
+			t.Errorf("%s is described in exports.json but not exported", fn.Name)
+			continue
+		}
+
+		var params []ast.Expr
+		for _, field := range decl.Type.Params.List {
+			for range max(len(field.Names), 1) {
+				params = append(params, field.Type)
+			}
+		}
+		if len(params) != len(fn.Args) {
+			t.Errorf("%s takes %v args but exports.json describes %v", fn.Name, len(params), len(fn.Args))
+			continue
+		}
+		for i, arg := range fn.Args {
+			if types := specTypes(params[i], false); !contains(types, arg.Type) {
+				t.Errorf("%s arg %s is %s in exports.json but %v in Go", fn.Name, arg.Name, arg.Type, types)
+			}
+		}
+
+		returns := []string{"void"}
+		if results := decl.Type.Results; results != nil && len(results.List) > 0 {
+			returns = specTypes(results.List[0].Type, true)
+		}
+		if !contains(returns, fn.Returns) {
+			t.Errorf("%s returns %s in exports.json but %v in Go", fn.Name, fn.Returns, returns)
+		}
+	}
+	for name := range exports {
+		if !described[name] {
+			t.Errorf("%s is exported but missing from exports.json", name)
+		}
+	}
+}
+
+// TestGeneratedFilesAreUpToDate fails when exports.json was changed without running go generate ./sharedlib
+func TestGeneratedFilesAreUpToDate(t *testing.T) {
+	spec := readSpec(t)
+	outputs := []struct {
+		path     string
+		generate func(*Spec) string
+	}{
+		{"../lighter.h", generateHeader},
+		{"../../bindings/python/lighter_signer.py", generatePython},
+		{"../../bindings/node/lighter_signer.ts", generateTypeScript},
+	}
+	for _, output := range outputs {
+		b, err := os.ReadFile(output.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != output.generate(spec) {
+			t.Errorf("%s is stale, run go generate ./sharedlib", output.path)
+		}
+	}
+}
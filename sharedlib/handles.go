@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/uncle-gua/lighter-go/client"
+)
+
+// clientEntry serializes the calls made with the same handle, so concurrent signing can't fetch the same nonce twice
+type clientEntry struct {
+	mu       sync.Mutex
+	txClient *client.TxClient
+}
+
+var (
+	errClientNotCreated = fmt.Errorf("client is not created, call CreateClient() first")
+	errInvalidHandle    = fmt.Errorf("invalid client handle")
+)
+
+var (
+	clientsMu  sync.RWMutex
+	clients    = make(map[int64]*clientEntry)
+	lastHandle int64
+
+	// legacyHandles are the clients created by CreateClient, by api key index. currentHandle is the one selected by SwitchAPIKey,
+	// used by the exports which don't take a handle.
+	legacyHandles = make(map[uint8]int64)
+	currentHandle int64
+)
+
+func newClientHandle(url, privateKey string, chainId uint32, apiKeyIndex uint8, accountIndex int64) (int64, error) {
+	if accountIndex <= 0 {
+		return 0, fmt.Errorf("invalid account index")
+	}
+
+	httpClient := client.NewHTTPClient(url)
+	txClient, err := client.NewTxClient(httpClient, privateKey, accountIndex, apiKeyIndex, chainId)
+	if err != nil {
+		return 0, fmt.Errorf("error occurred when creating TxClient. err: %v", err)
+	}
+
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	lastHandle++
+	clients[lastHandle] = &clientEntry{txClient: txClient}
+	return lastHandle, nil
+}
+
+func destroyClientHandle(handle int64) error {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	if _, ok := clients[handle]; !ok {
+		return fmt.Errorf("%w %v", errInvalidHandle, handle)
+	}
+	delete(clients, handle)
+	for apiKeyIndex, legacyHandle := range legacyHandles {
+		if legacyHandle == handle {
+			delete(legacyHandles, apiKeyIndex)
+		}
+	}
+	if currentHandle == handle {
+		currentHandle = 0
+	}
+	return nil
+}
+
+// withClient calls fn with the client of the handle, holding its lock
+func withClient(handle int64, fn func(txClient *client.TxClient) error) error {
+	clientsMu.RLock()
+	entry, ok := clients[handle]
+	clientsMu.RUnlock()
+	if !ok {
+		if handle == 0 {
+			return errClientNotCreated
+		}
+		return fmt.Errorf("%w %v", errInvalidHandle, handle)
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	return fn(entry.txClient)
+}
+
+// registerLegacyHandle makes the handle the current one & the one used for its api key index, replacing the previous client
+func registerLegacyHandle(handle int64, apiKeyIndex uint8) {
+	clientsMu.Lock()
+	previous, ok := legacyHandles[apiKeyIndex]
+	legacyHandles[apiKeyIndex] = handle
+	currentHandle = handle
+	clientsMu.Unlock()
+
+	if ok {
+		_ = destroyClientHandle(previous)
+	}
+}
+
+func legacyHandle(apiKeyIndex uint8) (int64, bool) {
+	clientsMu.RLock()
+	defer clientsMu.RUnlock()
+	handle, ok := legacyHandles[apiKeyIndex]
+	return handle, ok
+}
+
+func switchLegacyHandle(apiKeyIndex uint8) error {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	handle, ok := legacyHandles[apiKeyIndex]
+	if !ok {
+		return fmt.Errorf("no client initialized for api key")
+	}
+	currentHandle = handle
+	return nil
+}
+
+func currentLegacyHandle() int64 {
+	clientsMu.RLock()
+	defer clientsMu.RUnlock()
+	return currentHandle
+}
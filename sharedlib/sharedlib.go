@@ -1,175 +1,104 @@
 package main
 
+//go:generate go run ./bindgen -spec exports.json -header lighter.h -python ../bindings/python/lighter_signer.py -typescript ../bindings/node/lighter_signer.ts
+
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
+	"unsafe"
 
-	curve "github.com/elliottech/poseidon_crypto/curve/ecgfp5"
-	schnorr "github.com/elliottech/poseidon_crypto/signature/schnorr"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/uncle-gua/lighter-go/client"
+	"github.com/uncle-gua/lighter-go/signer"
 	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
 )
 
 /*
 #include <stdlib.h>
-typedef struct {
-	char* str;
-	char* err;
-} StrOrErr;
-
-typedef struct {
-	char* privateKey;
-	char* publicKey;
-	char* err;
-} ApiKeyResponse;
+#include "lighter.h"
 */
 import "C"
 
-var (
-	txClient        *client.TxClient
-	backupTxClients map[uint8]*client.TxClient
-)
-
-func wrapErr(err error) (ret *C.char) {
-	return C.CString(fmt.Sprintf("%v", err))
-}
-
-//export GenerateAPIKey
-func GenerateAPIKey(cSeed *C.char) (ret C.ApiKeyResponse) {
-	var err error
-	var privateKeyStr string
-	var publicKeyStr string
-
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.ApiKeyResponse{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.ApiKeyResponse{
-				privateKey: C.CString(privateKeyStr),
-				publicKey:  C.CString(publicKeyStr),
-			}
-		}
-	}()
-
-	seed := C.GoString(cSeed)
-	seedP := &seed
-	if seed == "" {
-		seedP = nil
-	}
-
-	key := curve.SampleScalar(seedP)
-
-	publicKeyStr = hexutil.Encode(schnorr.SchnorrPkFromSk(key).ToLittleEndianBytes())
-	privateKeyStr = hexutil.Encode(key.ToLittleEndianBytes())
+// allocations counts the strings returned to the caller which were not freed yet
+var allocations atomic.Int64
 
-	return
+// cString allocates a C string, which the caller must release with one of the Free* exports
+func cString(s string) *C.char {
+	allocations.Add(1)
+	return C.CString(s)
 }
 
-//export CreateClient
-func CreateClient(cUrl *C.char, cPrivateKey *C.char, cChainId C.int, cApiKeyIndex C.int, cAccountIndex C.longlong) (ret *C.char) {
-	var err error
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = wrapErr(err)
-		}
-	}()
-
-	url := C.GoString(cUrl)
-	privateKey := C.GoString(cPrivateKey)
-	chainId := uint32(cChainId)
-	apiKeyIndex := uint8(cApiKeyIndex)
-	accountIndex := int64(cAccountIndex)
-
-	if accountIndex <= 0 {
-		err = fmt.Errorf("invalid account index")
-		return
-	}
-
-	httpClient := client.NewHTTPClient(url)
-	txClient, err = client.NewTxClient(httpClient, privateKey, accountIndex, apiKeyIndex, chainId)
-	if err != nil {
-		err = fmt.Errorf("error occurred when creating TxClient. err: %v", err)
+func freeString(s *C.char) {
+	if s == nil {
 		return
 	}
-	if backupTxClients == nil {
-		backupTxClients = make(map[uint8]*client.TxClient)
-	}
-	backupTxClients[apiKeyIndex] = txClient
+	allocations.Add(-1)
+	C.free(unsafe.Pointer(s))
+}
 
-	return nil
+func wrapErr(err error) (ret *C.char) {
+	return cString(fmt.Sprintf("%v", err))
 }
 
-//export CheckClient
-func CheckClient(cApiKeyIndex C.int, cAccountIndex C.longlong) (ret *C.char) {
-	var err error
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = wrapErr(err)
-		}
-	}()
+//export FreeString
+func FreeString(s *C.char) {
+	freeString(s)
+}
 
-	apiKeyIndex := uint8(cApiKeyIndex)
-	accountIndex := int64(cAccountIndex)
+//export FreeStrOrErr
+func FreeStrOrErr(result C.StrOrErr) {
+	freeString(result.str)
+	freeString(result.err)
+}
 
-	client, ok := backupTxClients[apiKeyIndex]
-	if !ok {
-		err = fmt.Errorf("api key not registered")
-		return
-	}
+//export FreeApiKeyResponse
+func FreeApiKeyResponse(result C.ApiKeyResponse) {
+	freeString(result.privateKey)
+	freeString(result.publicKey)
+	freeString(result.err)
+}
 
-	if client.GetApiKeyIndex() != apiKeyIndex {
-		err = fmt.Errorf("apiKeyIndex does not match. expected %v but got %v", client.GetApiKeyIndex(), apiKeyIndex)
-		return
-	}
-	if client.GetAccountIndex() != accountIndex {
-		err = fmt.Errorf("accountIndex does not match. expected %v but got %v", client.GetAccountIndex(), accountIndex)
-		return
-	}
+//export FreeHandleOrErr
+func FreeHandleOrErr(result C.HandleOrErr) {
+	freeString(result.err)
+}
 
-	// check that the API key registered on Lighter matches this one
-	key, err := client.HTTP().GetApiKey(accountIndex, apiKeyIndex)
-	if err != nil {
-		err = fmt.Errorf("failed to get Api Keys. err: %v", err)
-		return
-	}
+//export FreeLighterResult
+func FreeLighterResult(result C.LighterResult) {
+	freeString(result.txInfo)
+	freeString(result.txHash)
+	freeString(result.messageToSign)
+	freeString(result.err)
+}
 
-	pubKeyBytes := client.GetKeyManager().PubKeyBytes()
-	pubKeyStr := hexutil.Encode(pubKeyBytes[:])
-	pubKeyStr = strings.Replace(pubKeyStr, "0x", "", 1)
+//export OutstandingAllocations
+func OutstandingAllocations() C.longlong {
+	return C.longlong(allocations.Load())
+}
 
-	ak := key.ApiKeys[0]
-	if ak.PublicKey != pubKeyStr {
-		err = fmt.Errorf("private key does not match the one on Lighter. ownPubKey: %s response: %+v", pubKeyStr, ak)
-		return
+// errCode maps the error to the LIGHTER_ERR_* codes of LighterResult
+func errCode(err error) C.int {
+	switch {
+	case err == nil:
+		return C.LIGHTER_OK
+	case errors.Is(err, errClientNotCreated), errors.Is(err, errInvalidHandle):
+		return C.LIGHTER_ERR_INVALID_HANDLE
+	case errors.Is(err, client.ErrInvalidTxRequest):
+		return C.LIGHTER_ERR_INVALID_REQUEST
+	default:
+		return C.LIGHTER_ERR_TX
 	}
-
-	return
 }
 
-//export SignChangePubKey
-func SignChangePubKey(cPubKey *C.char, cNonce C.longlong) (ret C.StrOrErr) {
-	// Note: The ChangePubKey TX needs to be signed by the API key that's being changed to as well.
-	//       Because of that, there's no reason to add the params for apiKeyIndex & accountIndex, because this
-	//       version of the SDK doesn't have support for multiple signers.
-	//       Even if it'd had, the flow would look something like this:
-	//       - first you select which client you're sending the TX from
-	//       - then we use the ApiKeyIndex & AccountIndex from that client
+// withHandle calls fn with the client of the handle, recovering from panics, and wraps the result
+func withHandle(cHandle C.longlong, fn func(txClient *client.TxClient) (string, error)) (ret C.StrOrErr) {
 	var err error
-	var txInfoStr string
+	var str string
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -181,141 +110,92 @@ func SignChangePubKey(cPubKey *C.char, cNonce C.longlong) (ret C.StrOrErr) {
 			}
 		} else {
 			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
+				str: cString(str),
 			}
 		}
 	}()
 
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
-
-	nonce := int64(cNonce)
+	err = withClient(int64(cHandle), func(txClient *client.TxClient) (err error) {
+		str, err = fn(txClient)
+		return err
+	})
+	return
+}
 
-	// handle PubKey
-	pubKeyStr := C.GoString(cPubKey)
-	pubKeyBytes, err := hexutil.Decode(pubKeyStr)
-	if err != nil {
-		return
-	}
-	if len(pubKeyBytes) != 40 {
-		err = fmt.Errorf("invalid pub key length. expected 40 but got %v", len(pubKeyBytes))
-		return
-	}
-	var pubKey [40]byte
-	copy(pubKey[:], pubKeyBytes)
+func current() C.longlong {
+	return C.longlong(currentLegacyHandle())
+}
 
-	txInfo := &types.ChangePubKeyReq{
-		PubKey: pubKey,
-	}
-	ops := &types.TransactOpts{}
+func nonceOps(nonce int64) *types.TransactOpts {
+	ops := new(types.TransactOpts)
 	if nonce != -1 {
 		ops.Nonce = &nonce
 	}
+	return ops
+}
 
-	tx, err := txClient.GetChangePubKeyTransaction(txInfo, ops)
+func marshalTx(tx any) (string, error) {
+	txInfoBytes, err := json.Marshal(tx)
 	if err != nil {
-		return
+		return "", err
 	}
+	return string(txInfoBytes), nil
+}
 
-	// === manually add MessageToSign to the response:
+// marshalTxWithMessageToSign adds the MessageToSign field, which has to be signed by the L1 address of the account, to the tx
+func marshalTxWithMessageToSign(tx any, messageToSign string) (string, error) {
 	// - marshal the tx
 	// - unmarshal it into a generic map
 	// - add the new field
 	// - marshal it again
 	txInfoBytes, err := json.Marshal(tx)
 	if err != nil {
-		return
+		return "", err
 	}
 	obj := make(map[string]interface{})
-	err = json.Unmarshal(txInfoBytes, &obj)
-	obj["MessageToSign"] = tx.GetL1SignatureBody()
-	txInfoBytes, err = json.Marshal(obj)
-	if err != nil {
-		return
+	if err := json.Unmarshal(txInfoBytes, &obj); err != nil {
+		return "", err
 	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+	obj["MessageToSign"] = messageToSign
+	return marshalTx(obj)
 }
 
-//export SignCreateOrder
-func SignCreateOrder(cMarketIndex C.int, cClientOrderIndex C.longlong, cBaseAmount C.longlong, cPrice C.int, cIsAsk C.int, cOrderType C.int, cTimeInForce C.int, cReduceOnly C.int, cTriggerPrice C.int, cOrderExpiry C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+//export GenerateAPIKey
+func GenerateAPIKey(cSeed *C.char) (ret C.ApiKeyResponse) {
 	var err error
-	var txInfoStr string
+	var privateKeyStr string
+	var publicKeyStr string
 
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("%v", r)
 		}
 		if err != nil {
-			ret = C.StrOrErr{
+			ret = C.ApiKeyResponse{
 				err: wrapErr(err),
 			}
 		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
+			ret = C.ApiKeyResponse{
+				privateKey: cString(privateKeyStr),
+				publicKey:  cString(publicKeyStr),
 			}
 		}
 	}()
 
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
-
-	marketIndex := uint8(cMarketIndex)
-	clientOrderIndex := int64(cClientOrderIndex)
-	baseAmount := int64(cBaseAmount)
-	price := uint32(cPrice)
-	isAsk := uint8(cIsAsk)
-	orderType := uint8(cOrderType)
-	timeInForce := uint8(cTimeInForce)
-	reduceOnly := uint8(cReduceOnly)
-	triggerPrice := uint32(cTriggerPrice)
-	orderExpiry := int64(cOrderExpiry)
-	nonce := int64(cNonce)
-
-	if orderExpiry == -1 {
-		orderExpiry = time.Now().Add(time.Hour * 24 * 28).UnixMilli() // 28 days
-	}
-
-	txInfo := &types.CreateOrderTxReq{
-		MarketIndex:      marketIndex,
-		ClientOrderIndex: clientOrderIndex,
-		BaseAmount:       baseAmount,
-		Price:            price,
-		IsAsk:            isAsk,
-		Type:             orderType,
-		TimeInForce:      timeInForce,
-		ReduceOnly:       reduceOnly,
-		TriggerPrice:     triggerPrice,
-		OrderExpiry:      orderExpiry,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetCreateOrderTransaction(txInfo, ops)
-	if err != nil {
-		return
-	}
+	seed := C.GoString(cSeed)
+	key := signer.GenerateKeyManager(seed)
+	pubKey := key.PubKeyBytes()
 
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
-	}
+	publicKeyStr = hexutil.Encode(pubKey[:])
+	privateKeyStr = hexutil.Encode(key.PrvKeyBytes())
 
-	txInfoStr = string(txInfoBytes)
 	return
 }
 
-//export SignCancelOrder
-func SignCancelOrder(cMarketIndex C.int, cOrderIndex C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+// strOrErr wraps the result of fn, recovering from panics
+func strOrErr(fn func() (string, error)) (ret C.StrOrErr) {
 	var err error
-	var txInfoStr string
+	var str string
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -327,696 +207,646 @@ func SignCancelOrder(cMarketIndex C.int, cOrderIndex C.longlong, cNonce C.longlo
 			}
 		} else {
 			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
+				str: cString(str),
 			}
 		}
 	}()
 
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
-
-	marketIndex := uint8(cMarketIndex)
-	orderIndex := int64(cOrderIndex)
-	nonce := int64(cNonce)
+	str, err = fn()
+	return
+}
 
-	txInfo := &types.CancelOrderTxReq{
-		MarketIndex: marketIndex,
-		Index:       orderIndex,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
+func decodeKey(key string, size int) ([]byte, error) {
+	if !strings.HasPrefix(key, "0x") {
+		key = "0x" + key
 	}
-
-	tx, err := txClient.GetCancelOrderTransaction(txInfo, ops)
+	b, err := hexutil.Decode(key)
 	if err != nil {
-		return
+		return nil, err
 	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
+	if len(b) != size {
+		return nil, fmt.Errorf("invalid key length. expected %v but got %v", size, len(b))
 	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+	return b, nil
 }
 
-//export SignWithdraw
-func SignWithdraw(cUSDCAmount C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
-
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
+//export DerivePublicKey
+func DerivePublicKey(cPrivateKey *C.char) (ret C.StrOrErr) {
+	return strOrErr(func() (string, error) {
+		privateKey, err := decodeKey(C.GoString(cPrivateKey), 40)
+		if err != nil {
+			return "", err
 		}
+		keyManager, err := signer.NewKeyManager(privateKey)
 		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
+			return "", err
 		}
-	}()
-
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
+		pubKey := keyManager.PubKeyBytes()
+		return hexutil.Encode(pubKey[:]), nil
+	})
+}
 
-	usdcAmount := uint64(cUSDCAmount)
-	nonce := int64(cNonce)
+// ComputeTxHash returns the hash of the tx_info, which is what the API key signs & the TxHash returned by Lighter, without needing a signature
+//
+//export ComputeTxHash
+func ComputeTxHash(cTxType C.int, cTxInfo *C.char, cChainId C.int) (ret C.StrOrErr) {
+	return strOrErr(func() (string, error) {
+		tx, err := txtypes.ParseTxInfo(uint8(cTxType), []byte(C.GoString(cTxInfo)))
+		if err != nil {
+			return "", err
+		}
+		return types.ComputeTxHash(tx, uint32(cChainId))
+	})
+}
 
-	txInfo := types.WithdrawTxReq{
-		USDCAmount: usdcAmount,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
+// VerifyTxSignature returns NULL if the Sig of the tx_info was made by the API key with the public key, or the reason it's invalid
+//
+//export VerifyTxSignature
+func VerifyTxSignature(cTxType C.int, cTxInfo *C.char, cPubKey *C.char, cChainId C.int) (ret *C.char) {
+	result := strOrErr(func() (string, error) {
+		txInfo := []byte(C.GoString(cTxInfo))
+		tx, err := txtypes.ParseTxInfo(uint8(cTxType), txInfo)
+		if err != nil {
+			return "", err
+		}
+		signed := struct{ Sig []byte }{}
+		if err := json.Unmarshal(txInfo, &signed); err != nil {
+			return "", err
+		}
+		if len(signed.Sig) == 0 {
+			return "", fmt.Errorf("tx is not signed")
+		}
+		pubKey, err := decodeKey(C.GoString(cPubKey), 40)
+		if err != nil {
+			return "", err
+		}
+		return "", types.VerifyTxSignature(tx, signed.Sig, pubKey, uint32(cChainId))
+	})
+	freeString(result.str)
+	return result.err
+}
 
-	tx, err := txClient.GetWithdrawTransaction(&txInfo, ops)
-	if err != nil {
-		return
-	}
+// GetL1SignatureBody returns the message to be signed by the L1 address of the account, for ChangePubKey & Transfer tx_infos
+//
+//export GetL1SignatureBody
+func GetL1SignatureBody(cTxType C.int, cTxInfo *C.char) (ret C.StrOrErr) {
+	return strOrErr(func() (string, error) {
+		tx, err := txtypes.ParseTxInfo(uint8(cTxType), []byte(C.GoString(cTxInfo)))
+		if err != nil {
+			return "", err
+		}
+		l1Tx, ok := tx.(client.L1SignedTx)
+		if !ok {
+			return "", fmt.Errorf("tx type %v has no L1 signature", cTxType)
+		}
+		return l1Tx.GetL1SignatureBody(), nil
+	})
+}
 
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
-	}
+// ParseAuthToken returns the fields of the auth token as JSON: deadline (unix seconds), account_index & api_key_index. The signature isn't verified.
+//
+//export ParseAuthToken
+func ParseAuthToken(cToken *C.char) (ret C.StrOrErr) {
+	return strOrErr(func() (string, error) {
+		token, err := types.ParseAuthToken(C.GoString(cToken))
+		if err != nil {
+			return "", err
+		}
+		return marshalTx(map[string]any{
+			"deadline":      token.Deadline.Unix(),
+			"account_index": token.AccountIndex,
+			"api_key_index": token.ApiKeyIndex,
+		})
+	})
+}
 
-	txInfoStr = string(txInfoBytes)
-	return
+// VerifyAuthToken returns NULL if the auth token was signed by the API key with the public key and hasn't expired, or the reason it's invalid
+//
+//export VerifyAuthToken
+func VerifyAuthToken(cToken *C.char, cPubKey *C.char) (ret *C.char) {
+	result := strOrErr(func() (string, error) {
+		token, err := types.ParseAuthToken(C.GoString(cToken))
+		if err != nil {
+			return "", err
+		}
+		pubKey, err := decodeKey(C.GoString(cPubKey), 40)
+		if err != nil {
+			return "", err
+		}
+		return "", token.Verify(pubKey)
+	})
+	freeString(result.str)
+	return result.err
 }
 
-//export SignCreateSubAccount
-func SignCreateSubAccount(cNonce C.longlong) (ret C.StrOrErr) {
+// CreateClientHandle creates a client which is only used by the *WithHandle exports given the returned handle,
+// so a process can sign for several accounts & API keys at once. Calls using the same handle are serialized.
+//
+//export CreateClientHandle
+func CreateClientHandle(cUrl *C.char, cPrivateKey *C.char, cChainId C.int, cApiKeyIndex C.int, cAccountIndex C.longlong) (ret C.HandleOrErr) {
 	var err error
-	var txInfoStr string
+	var handle int64
 
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("%v", r)
 		}
 		if err != nil {
-			ret = C.StrOrErr{
+			ret = C.HandleOrErr{
 				err: wrapErr(err),
 			}
 		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
+			ret = C.HandleOrErr{
+				handle: C.longlong(handle),
 			}
 		}
 	}()
 
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
-
-	nonce := int64(cNonce)
-
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetCreateSubAccountTransaction(ops)
-	if err != nil {
-		return
-	}
+	handle, err = newClientHandle(C.GoString(cUrl), C.GoString(cPrivateKey), uint32(cChainId), uint8(cApiKeyIndex), int64(cAccountIndex))
+	return
+}
 
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
+//export DestroyClientHandle
+func DestroyClientHandle(cHandle C.longlong) (ret *C.char) {
+	if err := destroyClientHandle(int64(cHandle)); err != nil {
+		return wrapErr(err)
 	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+	return nil
 }
 
-//export SignCancelAllOrders
-func SignCancelAllOrders(cTimeInForce C.int, cTime C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+//export CreateClient
+func CreateClient(cUrl *C.char, cPrivateKey *C.char, cChainId C.int, cApiKeyIndex C.int, cAccountIndex C.longlong) (ret *C.char) {
 	var err error
-	var txInfoStr string
-
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("%v", r)
 		}
 		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
+			ret = wrapErr(err)
 		}
 	}()
 
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
+	handle, err := newClientHandle(C.GoString(cUrl), C.GoString(cPrivateKey), uint32(cChainId), uint8(cApiKeyIndex), int64(cAccountIndex))
+	if err != nil {
 		return
 	}
+	registerLegacyHandle(handle, uint8(cApiKeyIndex))
+
+	return nil
+}
 
-	timeInForce := uint8(cTimeInForce)
-	t := int64(cTime)
-	nonce := int64(cNonce)
+// checkClient checks that the API key registered on Lighter matches the one of the client
+func checkClient(txClient *client.TxClient) error {
+	accountIndex := txClient.GetAccountIndex()
+	apiKeyIndex := txClient.GetApiKeyIndex()
 
-	txInfo := &types.CancelAllOrdersTxReq{
-		TimeInForce: timeInForce,
-		Time:        t,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
+	key, err := txClient.HTTP().GetApiKey(accountIndex, apiKeyIndex)
+	if err != nil {
+		return fmt.Errorf("failed to get Api Keys. err: %v", err)
 	}
 
-	tx, err := txClient.GetCancelAllOrdersTransaction(txInfo, ops)
-	if err != nil {
-		return
-	}
+	pubKeyBytes := txClient.GetKeyManager().PubKeyBytes()
+	pubKeyStr := hexutil.Encode(pubKeyBytes[:])
+	pubKeyStr = strings.Replace(pubKeyStr, "0x", "", 1)
 
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
+	ak := key.ApiKeys[0]
+	if ak.PublicKey != pubKeyStr {
+		return fmt.Errorf("private key does not match the one on Lighter. ownPubKey: %s response: %+v", pubKeyStr, ak)
 	}
+	return nil
+}
 
-	txInfoStr = string(txInfoBytes)
-	return
+//export CheckClientHandle
+func CheckClientHandle(cHandle C.longlong) (ret *C.char) {
+	result := withHandle(cHandle, func(txClient *client.TxClient) (string, error) {
+		return "", checkClient(txClient)
+	})
+	freeString(result.str)
+	return result.err
 }
 
-//export SignModifyOrder
-func SignModifyOrder(cMarketIndex C.int, cIndex C.longlong, cBaseAmount C.longlong, cPrice C.longlong, cTriggerPrice C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
+//export CheckClient
+func CheckClient(cApiKeyIndex C.int, cAccountIndex C.longlong) (ret *C.char) {
+	apiKeyIndex := uint8(cApiKeyIndex)
+	accountIndex := int64(cAccountIndex)
 
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
+	handle, ok := legacyHandle(apiKeyIndex)
+	if !ok {
+		return wrapErr(fmt.Errorf("api key not registered"))
+	}
+
+	result := withHandle(C.longlong(handle), func(txClient *client.TxClient) (string, error) {
+		if txClient.GetApiKeyIndex() != apiKeyIndex {
+			return "", fmt.Errorf("apiKeyIndex does not match. expected %v but got %v", txClient.GetApiKeyIndex(), apiKeyIndex)
+		}
+		if txClient.GetAccountIndex() != accountIndex {
+			return "", fmt.Errorf("accountIndex does not match. expected %v but got %v", txClient.GetAccountIndex(), accountIndex)
 		}
+		return "", checkClient(txClient)
+	})
+	freeString(result.str)
+	return result.err
+}
+
+// SignChangePubKeyWithHandle Note: The ChangePubKey TX needs to be signed by the API key that's being changed to as well,
+// so the client of the handle should be created with the new private key, the account index & the api key index being changed.
+//
+//export SignChangePubKeyWithHandle
+func SignChangePubKeyWithHandle(cHandle C.longlong, cPubKey *C.char, cNonce C.longlong) (ret C.StrOrErr) {
+	return withHandle(cHandle, func(txClient *client.TxClient) (string, error) {
+		// handle PubKey
+		pubKeyBytes, err := hexutil.Decode(C.GoString(cPubKey))
 		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
+			return "", err
 		}
-	}()
+		if len(pubKeyBytes) != 40 {
+			return "", fmt.Errorf("invalid pub key length. expected 40 but got %v", len(pubKeyBytes))
+		}
+		var pubKey [40]byte
+		copy(pubKey[:], pubKeyBytes)
 
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
+		txInfo := &types.ChangePubKeyReq{
+			PubKey: pubKey,
+		}
+		tx, err := txClient.GetChangePubKeyTransaction(txInfo, nonceOps(int64(cNonce)))
+		if err != nil {
+			return "", err
+		}
+		return marshalTxWithMessageToSign(tx, tx.GetL1SignatureBody())
+	})
+}
 
-	marketIndex := uint8(cMarketIndex)
-	index := int64(cIndex)
-	baseAmount := int64(cBaseAmount)
-	price := uint32(cPrice)
-	triggerPrice := uint32(cTriggerPrice)
-	nonce := int64(cNonce)
-
-	txInfo := &types.ModifyOrderTxReq{
-		MarketIndex:  marketIndex,
-		Index:        index,
-		BaseAmount:   baseAmount,
-		Price:        price,
-		TriggerPrice: triggerPrice,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
+//export SignChangePubKey
+func SignChangePubKey(cPubKey *C.char, cNonce C.longlong) (ret C.StrOrErr) {
+	return SignChangePubKeyWithHandle(current(), cPubKey, cNonce)
+}
 
-	tx, err := txClient.GetModifyOrderTransaction(txInfo, ops)
-	if err != nil {
-		return
-	}
+//export SignCreateOrderWithHandle
+func SignCreateOrderWithHandle(cHandle C.longlong, cMarketIndex C.int, cClientOrderIndex C.longlong, cBaseAmount C.longlong, cPrice C.int, cIsAsk C.int, cOrderType C.int, cTimeInForce C.int, cReduceOnly C.int, cTriggerPrice C.int, cOrderExpiry C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	return withHandle(cHandle, func(txClient *client.TxClient) (string, error) {
+		orderExpiry := int64(cOrderExpiry)
+		if orderExpiry == -1 {
+			orderExpiry = time.Now().Add(time.Hour * 24 * 28).UnixMilli() // 28 days
+		}
+
+		txInfo := &types.CreateOrderTxReq{
+			MarketIndex:      uint8(cMarketIndex),
+			ClientOrderIndex: int64(cClientOrderIndex),
+			BaseAmount:       int64(cBaseAmount),
+			Price:            uint32(cPrice),
+			IsAsk:            uint8(cIsAsk),
+			Type:             uint8(cOrderType),
+			TimeInForce:      uint8(cTimeInForce),
+			ReduceOnly:       uint8(cReduceOnly),
+			TriggerPrice:     uint32(cTriggerPrice),
+			OrderExpiry:      orderExpiry,
+		}
+		tx, err := txClient.GetCreateOrderTransaction(txInfo, nonceOps(int64(cNonce)))
+		if err != nil {
+			return "", err
+		}
+		return marshalTx(tx)
+	})
+}
 
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
-	}
+//export SignCreateOrder
+func SignCreateOrder(cMarketIndex C.int, cClientOrderIndex C.longlong, cBaseAmount C.longlong, cPrice C.int, cIsAsk C.int, cOrderType C.int, cTimeInForce C.int, cReduceOnly C.int, cTriggerPrice C.int, cOrderExpiry C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	return SignCreateOrderWithHandle(current(), cMarketIndex, cClientOrderIndex, cBaseAmount, cPrice, cIsAsk, cOrderType, cTimeInForce, cReduceOnly, cTriggerPrice, cOrderExpiry, cNonce)
+}
 
-	txInfoStr = string(txInfoBytes)
-	return
+//export SignCancelOrderWithHandle
+func SignCancelOrderWithHandle(cHandle C.longlong, cMarketIndex C.int, cOrderIndex C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	return withHandle(cHandle, func(txClient *client.TxClient) (string, error) {
+		txInfo := &types.CancelOrderTxReq{
+			MarketIndex: uint8(cMarketIndex),
+			Index:       int64(cOrderIndex),
+		}
+		tx, err := txClient.GetCancelOrderTransaction(txInfo, nonceOps(int64(cNonce)))
+		if err != nil {
+			return "", err
+		}
+		return marshalTx(tx)
+	})
 }
 
-//export SignTransfer
-func SignTransfer(cToAccountIndex C.longlong, cUSDCAmount C.longlong, cFee C.longlong, cMemo *C.char, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
+//export SignCancelOrder
+func SignCancelOrder(cMarketIndex C.int, cOrderIndex C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	return SignCancelOrderWithHandle(current(), cMarketIndex, cOrderIndex, cNonce)
+}
 
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
+//export SignWithdrawWithHandle
+func SignWithdrawWithHandle(cHandle C.longlong, cUSDCAmount C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	return withHandle(cHandle, func(txClient *client.TxClient) (string, error) {
+		txInfo := &types.WithdrawTxReq{
+			USDCAmount: uint64(cUSDCAmount),
 		}
+		tx, err := txClient.GetWithdrawTransaction(txInfo, nonceOps(int64(cNonce)))
 		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
+			return "", err
 		}
-	}()
+		return marshalTx(tx)
+	})
+}
 
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
+//export SignWithdraw
+func SignWithdraw(cUSDCAmount C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	return SignWithdrawWithHandle(current(), cUSDCAmount, cNonce)
+}
 
-	toAccountIndex := int64(cToAccountIndex)
-	usdcAmount := int64(cUSDCAmount)
-	nonce := int64(cNonce)
-	fee := int64(cFee)
-	memo := [32]byte{}
-	memoStr := C.GoString(cMemo)
-	if len(memoStr) != 32 {
-		err = fmt.Errorf("memo expected to be 32 bytes long")
-		return
-	}
-	for i := 0; i < 32; i++ {
-		memo[i] = byte(memoStr[i])
-	}
+//export SignCreateSubAccountWithHandle
+func SignCreateSubAccountWithHandle(cHandle C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	return withHandle(cHandle, func(txClient *client.TxClient) (string, error) {
+		tx, err := txClient.GetCreateSubAccountTransaction(nonceOps(int64(cNonce)))
+		if err != nil {
+			return "", err
+		}
+		return marshalTx(tx)
+	})
+}
 
-	txInfo := &types.TransferTxReq{
-		ToAccountIndex: toAccountIndex,
-		USDCAmount:     usdcAmount,
-		Fee:            fee,
-		Memo:           memo,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
+//export SignCreateSubAccount
+func SignCreateSubAccount(cNonce C.longlong) (ret C.StrOrErr) {
+	return SignCreateSubAccountWithHandle(current(), cNonce)
+}
 
-	tx, err := txClient.GetTransferTransaction(txInfo, ops)
-	if err != nil {
-		return
-	}
+//export SignCancelAllOrdersWithHandle
+func SignCancelAllOrdersWithHandle(cHandle C.longlong, cTimeInForce C.int, cTime C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	return withHandle(cHandle, func(txClient *client.TxClient) (string, error) {
+		txInfo := &types.CancelAllOrdersTxReq{
+			TimeInForce: uint8(cTimeInForce),
+			Time:        int64(cTime),
+		}
+		tx, err := txClient.GetCancelAllOrdersTransaction(txInfo, nonceOps(int64(cNonce)))
+		if err != nil {
+			return "", err
+		}
+		return marshalTx(tx)
+	})
+}
 
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
-	}
+//export SignCancelAllOrders
+func SignCancelAllOrders(cTimeInForce C.int, cTime C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	return SignCancelAllOrdersWithHandle(current(), cTimeInForce, cTime, cNonce)
+}
 
-	txInfoStr = string(txInfoBytes)
-	obj := make(map[string]interface{})
-	err = json.Unmarshal(txInfoBytes, &obj)
-	obj["MessageToSign"] = tx.GetL1SignatureBody()
-	txInfoBytes, err = json.Marshal(obj)
-	if err != nil {
-		return
-	}
+//export SignModifyOrderWithHandle
+func SignModifyOrderWithHandle(cHandle C.longlong, cMarketIndex C.int, cIndex C.longlong, cBaseAmount C.longlong, cPrice C.longlong, cTriggerPrice C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	return withHandle(cHandle, func(txClient *client.TxClient) (string, error) {
+		txInfo := &types.ModifyOrderTxReq{
+			MarketIndex:  uint8(cMarketIndex),
+			Index:        int64(cIndex),
+			BaseAmount:   int64(cBaseAmount),
+			Price:        uint32(cPrice),
+			TriggerPrice: uint32(cTriggerPrice),
+		}
+		tx, err := txClient.GetModifyOrderTransaction(txInfo, nonceOps(int64(cNonce)))
+		if err != nil {
+			return "", err
+		}
+		return marshalTx(tx)
+	})
+}
 
-	txInfoStr = string(txInfoBytes)
-	return
+//export SignModifyOrder
+func SignModifyOrder(cMarketIndex C.int, cIndex C.longlong, cBaseAmount C.longlong, cPrice C.longlong, cTriggerPrice C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	return SignModifyOrderWithHandle(current(), cMarketIndex, cIndex, cBaseAmount, cPrice, cTriggerPrice, cNonce)
 }
 
-//export SignCreatePublicPool
-func SignCreatePublicPool(cOperatorFee C.longlong, cInitialTotalShares C.longlong, cMinOperatorShareRate C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
+//export SignTransferWithHandle
+func SignTransferWithHandle(cHandle C.longlong, cToAccountIndex C.longlong, cUSDCAmount C.longlong, cFee C.longlong, cMemo *C.char, cNonce C.longlong) (ret C.StrOrErr) {
+	return withHandle(cHandle, func(txClient *client.TxClient) (string, error) {
+		memo := [32]byte{}
+		memoStr := C.GoString(cMemo)
+		if len(memoStr) != 32 {
+			return "", fmt.Errorf("memo expected to be 32 bytes long")
+		}
+		for i := 0; i < 32; i++ {
+			memo[i] = byte(memoStr[i])
+		}
 
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
+		txInfo := &types.TransferTxReq{
+			ToAccountIndex: int64(cToAccountIndex),
+			USDCAmount:     int64(cUSDCAmount),
+			Fee:            int64(cFee),
+			Memo:           memo,
 		}
+		tx, err := txClient.GetTransferTransaction(txInfo, nonceOps(int64(cNonce)))
 		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
+			return "", err
 		}
-	}()
-
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
-
-	operatorFee := int64(cOperatorFee)
-	initialTotalShares := int64(cInitialTotalShares)
-	minOperatorShareRate := int64(cMinOperatorShareRate)
-	nonce := int64(cNonce)
+		return marshalTxWithMessageToSign(tx, tx.GetL1SignatureBody())
+	})
+}
 
-	txInfo := &types.CreatePublicPoolTxReq{
-		OperatorFee:          operatorFee,
-		InitialTotalShares:   initialTotalShares,
-		MinOperatorShareRate: minOperatorShareRate,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
+//export SignTransfer
+func SignTransfer(cToAccountIndex C.longlong, cUSDCAmount C.longlong, cFee C.longlong, cMemo *C.char, cNonce C.longlong) (ret C.StrOrErr) {
+	return SignTransferWithHandle(current(), cToAccountIndex, cUSDCAmount, cFee, cMemo, cNonce)
+}
 
-	tx, err := txClient.GetCreatePublicPoolTransaction(txInfo, ops)
-	if err != nil {
-		return
-	}
+//export SignCreatePublicPoolWithHandle
+func SignCreatePublicPoolWithHandle(cHandle C.longlong, cOperatorFee C.longlong, cInitialTotalShares C.longlong, cMinOperatorShareRate C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	return withHandle(cHandle, func(txClient *client.TxClient) (string, error) {
+		txInfo := &types.CreatePublicPoolTxReq{
+			OperatorFee:          int64(cOperatorFee),
+			InitialTotalShares:   int64(cInitialTotalShares),
+			MinOperatorShareRate: int64(cMinOperatorShareRate),
+		}
+		tx, err := txClient.GetCreatePublicPoolTransaction(txInfo, nonceOps(int64(cNonce)))
+		if err != nil {
+			return "", err
+		}
+		return marshalTx(tx)
+	})
+}
 
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
-	}
+//export SignCreatePublicPool
+func SignCreatePublicPool(cOperatorFee C.longlong, cInitialTotalShares C.longlong, cMinOperatorShareRate C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	return SignCreatePublicPoolWithHandle(current(), cOperatorFee, cInitialTotalShares, cMinOperatorShareRate, cNonce)
+}
 
-	txInfoStr = string(txInfoBytes)
-	return
+//export SignUpdatePublicPoolWithHandle
+func SignUpdatePublicPoolWithHandle(cHandle C.longlong, cPublicPoolIndex C.longlong, cStatus C.int, cOperatorFee C.longlong, cMinOperatorShareRate C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	return withHandle(cHandle, func(txClient *client.TxClient) (string, error) {
+		txInfo := &types.UpdatePublicPoolTxReq{
+			PublicPoolIndex:      int64(cPublicPoolIndex),
+			Status:               uint8(cStatus),
+			OperatorFee:          int64(cOperatorFee),
+			MinOperatorShareRate: int64(cMinOperatorShareRate),
+		}
+		tx, err := txClient.GetUpdatePublicPoolTransaction(txInfo, nonceOps(int64(cNonce)))
+		if err != nil {
+			return "", err
+		}
+		return marshalTx(tx)
+	})
 }
 
 //export SignUpdatePublicPool
 func SignUpdatePublicPool(cPublicPoolIndex C.longlong, cStatus C.int, cOperatorFee C.longlong, cMinOperatorShareRate C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
+	return SignUpdatePublicPoolWithHandle(current(), cPublicPoolIndex, cStatus, cOperatorFee, cMinOperatorShareRate, cNonce)
+}
 
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
+//export SignMintSharesWithHandle
+func SignMintSharesWithHandle(cHandle C.longlong, cPublicPoolIndex C.longlong, cShareAmount C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	return withHandle(cHandle, func(txClient *client.TxClient) (string, error) {
+		txInfo := &types.MintSharesTxReq{
+			PublicPoolIndex: int64(cPublicPoolIndex),
+			ShareAmount:     int64(cShareAmount),
 		}
+		tx, err := txClient.GetMintSharesTransaction(txInfo, nonceOps(int64(cNonce)))
 		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
+			return "", err
 		}
-	}()
-
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
-
-	publicPoolIndex := int64(cPublicPoolIndex)
-	status := uint8(cStatus)
-	operatorFee := int64(cOperatorFee)
-	minOperatorShareRate := int64(cMinOperatorShareRate)
-	nonce := int64(cNonce)
-
-	txInfo := &types.UpdatePublicPoolTxReq{
-		PublicPoolIndex:      publicPoolIndex,
-		Status:               status,
-		OperatorFee:          operatorFee,
-		MinOperatorShareRate: minOperatorShareRate,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetUpdatePublicPoolTransaction(txInfo, ops)
-	if err != nil {
-		return
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
-	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+		return marshalTx(tx)
+	})
 }
 
 //export SignMintShares
 func SignMintShares(cPublicPoolIndex C.longlong, cShareAmount C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
+	return SignMintSharesWithHandle(current(), cPublicPoolIndex, cShareAmount, cNonce)
+}
 
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
+//export SignBurnSharesWithHandle
+func SignBurnSharesWithHandle(cHandle C.longlong, cPublicPoolIndex C.longlong, cShareAmount C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	return withHandle(cHandle, func(txClient *client.TxClient) (string, error) {
+		txInfo := &types.BurnSharesTxReq{
+			PublicPoolIndex: int64(cPublicPoolIndex),
+			ShareAmount:     int64(cShareAmount),
 		}
+		tx, err := txClient.GetBurnSharesTransaction(txInfo, nonceOps(int64(cNonce)))
 		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
+			return "", err
 		}
-	}()
-
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
-
-	publicPoolIndex := int64(cPublicPoolIndex)
-	shareAmount := int64(cShareAmount)
-	nonce := int64(cNonce)
-
-	txInfo := &types.MintSharesTxReq{
-		PublicPoolIndex: publicPoolIndex,
-		ShareAmount:     shareAmount,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetMintSharesTransaction(txInfo, ops)
-	if err != nil {
-		return
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
-	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+		return marshalTx(tx)
+	})
 }
 
 //export SignBurnShares
 func SignBurnShares(cPublicPoolIndex C.longlong, cShareAmount C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
+	return SignBurnSharesWithHandle(current(), cPublicPoolIndex, cShareAmount, cNonce)
+}
 
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
+//export SignUpdateLeverageWithHandle
+func SignUpdateLeverageWithHandle(cHandle C.longlong, cMarketIndex C.int, cInitialMarginFraction C.int, cMarginMode C.int, cNonce C.longlong) (ret C.StrOrErr) {
+	return withHandle(cHandle, func(txClient *client.TxClient) (string, error) {
+		txInfo := &types.UpdateLeverageTxReq{
+			MarketIndex:           uint8(cMarketIndex),
+			InitialMarginFraction: uint16(cInitialMarginFraction),
+			MarginMode:            uint8(cMarginMode),
 		}
+		tx, err := txClient.GetUpdateLeverageTransaction(txInfo, nonceOps(int64(cNonce)))
 		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
+			return "", err
 		}
-	}()
-
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
-
-	publicPoolIndex := int64(cPublicPoolIndex)
-	shareAmount := int64(cShareAmount)
-	nonce := int64(cNonce)
-
-	txInfo := &types.BurnSharesTxReq{
-		PublicPoolIndex: publicPoolIndex,
-		ShareAmount:     shareAmount,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetBurnSharesTransaction(txInfo, ops)
-	if err != nil {
-		return
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
-	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+		return marshalTx(tx)
+	})
 }
 
 //export SignUpdateLeverage
 func SignUpdateLeverage(cMarketIndex C.int, cInitialMarginFraction C.int, cMarginMode C.int, cNonce C.longlong) (ret C.StrOrErr) {
+	return SignUpdateLeverageWithHandle(current(), cMarketIndex, cInitialMarginFraction, cMarginMode, cNonce)
+}
+
+// SignTx signs any tx type given its request as JSON, as described by client.TxClient.SignTxJSON.
+// messageToSign is only set for the txs which also need to be signed by the L1 address of the account.
+//
+//export SignTx
+func SignTx(cHandle C.longlong, cTxType C.int, cRequest *C.char) (ret C.LighterResult) {
 	var err error
-	var txInfoStr string
+	var code C.int
+	var txInfoStr, txHash, messageToSign string
 
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("%v", r)
+			code = C.LIGHTER_ERR_PANIC
 		}
 		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
+			if code == C.LIGHTER_OK {
+				code = errCode(err)
+			}
+			ret = C.LighterResult{
+				version: C.LIGHTER_RESULT_VERSION,
+				errCode: code,
+				err:     wrapErr(err),
 			}
 		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
+			ret = C.LighterResult{
+				version:       C.LIGHTER_RESULT_VERSION,
+				txInfo:        cString(txInfoStr),
+				txHash:        cString(txHash),
+				messageToSign: cString(messageToSign),
 			}
 		}
 	}()
 
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
-
-	marketIndex := uint8(cMarketIndex)
-	initialMarginFraction := uint16(cInitialMarginFraction)
-	nonce := int64(cNonce)
-	marginMode := uint8(cMarginMode)
-
-	txInfo := &types.UpdateLeverageTxReq{
-		MarketIndex:           marketIndex,
-		InitialMarginFraction: initialMarginFraction,
-		MarginMode:            uint8(marginMode),
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetUpdateLeverageTransaction(txInfo, ops)
-	if err != nil {
-		return
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
-	}
-
-	txInfoStr = string(txInfoBytes)
+	err = withClient(int64(cHandle), func(txClient *client.TxClient) error {
+		tx, err := txClient.SignTxJSON(uint8(cTxType), []byte(C.GoString(cRequest)))
+		if err != nil {
+			return err
+		}
+		txInfoStr, err = tx.GetTxInfo()
+		if err != nil {
+			return err
+		}
+		txHash = tx.GetTxHash()
+		if l1Tx, ok := tx.(client.L1SignedTx); ok {
+			messageToSign = l1Tx.GetL1SignatureBody()
+		}
+		return nil
+	})
 	return
 }
 
-// CreateAuthToken Note: in order for the deadline to be valid, it needs to be at most 8 hours from now.
+// CreateAuthTokenWithHandle Note: in order for the deadline to be valid, it needs to be at most 8 hours from now.
 // It's recommended that it'd be at most 7:55, as differences in clock times could make this
 // invalid. Still, this endpoint does not enforce that so users can generate the auth tokens in advance.
+//
+//export CreateAuthTokenWithHandle
+func CreateAuthTokenWithHandle(cHandle C.longlong, cDeadline C.longlong) (ret C.StrOrErr) {
+	return withHandle(cHandle, func(txClient *client.TxClient) (string, error) {
+		deadline := int64(cDeadline)
+		if deadline == 0 {
+			deadline = time.Now().Add(time.Hour * 7).Unix()
+		}
+		return txClient.GetAuthToken(time.Unix(deadline, 0))
+	})
+}
 
 //export CreateAuthToken
 func CreateAuthToken(cDeadline C.longlong) (ret C.StrOrErr) {
-	var err error
-	var authToken string
-
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(authToken),
-			}
-		}
-	}()
-
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
-
-	deadline := int64(cDeadline)
-	if deadline == 0 {
-		deadline = time.Now().Add(time.Hour * 7).Unix()
-	}
-
-	authToken, err = txClient.GetAuthToken(time.Unix(deadline, 0))
-	if err != nil {
-		return
-	}
-
-	return
+	return CreateAuthTokenWithHandle(current(), cDeadline)
 }
 
+// SwitchAPIKey selects which of the clients created by CreateClient is used by the exports which don't take a handle
+//
 //export SwitchAPIKey
 func SwitchAPIKey(c C.int) (ret *C.char) {
-	var err error
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = wrapErr(err)
-		}
-	}()
-
-	txClient = backupTxClients[uint8(c)]
-	if txClient == nil {
-		err = fmt.Errorf("no client initialized for api key")
+	if err := switchLegacyHandle(uint8(c)); err != nil {
+		return wrapErr(err)
 	}
-
-	return
+	return nil
 }
 
-//export SignUpdateMargin
-func SignUpdateMargin(cMarketIndex C.int, cUSDCAmount C.longlong, cDirection C.int, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
-	defer func() {
-		if r := recover(); r != nil {
-			wrapErr(fmt.Errorf("panic: %v", r))
+//export SignUpdateMarginWithHandle
+func SignUpdateMarginWithHandle(cHandle C.longlong, cMarketIndex C.int, cUSDCAmount C.longlong, cDirection C.int, cNonce C.longlong) (ret C.StrOrErr) {
+	return withHandle(cHandle, func(txClient *client.TxClient) (string, error) {
+		txInfo := &types.UpdateMarginTxReq{
+			MarketIndex: uint8(cMarketIndex),
+			USDCAmount:  int64(cUSDCAmount),
+			Direction:   uint8(cDirection),
 		}
+		tx, err := txClient.GetUpdateMarginTransaction(txInfo, nonceOps(int64(cNonce)))
 		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
+			return "", err
 		}
-	}()
-
-	if txClient == nil {
-		err = fmt.Errorf("Client is not created, call CreateClient() first")
-	}
-
-	marketIndex := uint8(cMarketIndex)
-	usdcAmount := int64(cUSDCAmount)
-	direction := uint8(cDirection)
-	nonce := int64(cNonce)
-
-	txInfo := &types.UpdateMarginTxReq{
-		MarketIndex: marketIndex,
-		USDCAmount:  usdcAmount,
-		Direction:   direction,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetUpdateMarginTransaction(txInfo, ops)
-
-	txInfoBytes, err := json.Marshal(tx)
-	txInfoStr = string(txInfoBytes)
+		return marshalTx(tx)
+	})
+}
 
-	return ret
+//export SignUpdateMargin
+func SignUpdateMargin(cMarketIndex C.int, cUSDCAmount C.longlong, cDirection C.int, cNonce C.longlong) (ret C.StrOrErr) {
+	return SignUpdateMarginWithHandle(current(), cMarketIndex, cUSDCAmount, cDirection, cNonce)
 }
 
 func main() {}
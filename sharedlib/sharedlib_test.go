@@ -0,0 +1,544 @@
+//go:build sharedlibtest
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+const (
+	testChainId      = 304
+	testApiKeyIndex  = 3
+	testAccountIndex = 7
+)
+
+// newTestHandle creates a client without HTTP client, so it only signs txs given their nonce.
+// Returns the handle along with the private & public keys of its API key.
+func newTestHandle(t *testing.T) (int64, string, string) {
+	t.Helper()
+	privateKey, publicKey, errStr := testApiKeyResponse(GenerateAPIKey(testArg(t.Cleanup, "")))
+	if errStr != "" {
+		t.Fatal(errStr)
+	}
+	handle, errStr := testHandleOrErr(CreateClientHandle(testArg(t.Cleanup, ""), testArg(t.Cleanup, privateKey), testChainId, testApiKeyIndex, testAccountIndex))
+	if errStr != "" {
+		t.Fatal(errStr)
+	}
+	t.Cleanup(func() {
+		testString(DestroyClientHandle(testLongLong(handle)))
+	})
+	return handle, privateKey, publicKey
+}
+
+func TestAllocationsAreFreed(t *testing.T) {
+	handle, privateKey, publicKey := newTestHandle(t)
+	h := testLongLong(handle)
+
+	transfer := testLighterResult(SignTx(h, txtypes.TxTypeL2Transfer, testArg(t.Cleanup, `{"ToAccountIndex":8,"USDCAmount":1000000,"Nonce":1}`)))
+	if transfer.err != "" {
+		t.Fatal(transfer.err)
+	}
+	token, errStr := testStrOrErr(CreateAuthTokenWithHandle(h, 0))
+	if errStr != "" {
+		t.Fatal(errStr)
+	}
+	memo := testArg(t.Cleanup, strings.Repeat("m", 32))
+	txInfo := testArg(t.Cleanup, transfer.txInfo)
+	invalid := testArg(t.Cleanup, "invalid")
+
+	// every export returning memory, on its success & error paths when it has both
+	calls := []struct {
+		name string
+		call func()
+	}{
+		{"GenerateAPIKey", func() { testApiKeyResponse(GenerateAPIKey(testArg(t.Cleanup, ""))) }},
+		{"DerivePublicKey", func() { testStrOrErr(DerivePublicKey(testArg(t.Cleanup, privateKey))) }},
+		{"DerivePublicKey error", func() { testStrOrErr(DerivePublicKey(invalid)) }},
+		{"ComputeTxHash", func() { testStrOrErr(ComputeTxHash(txtypes.TxTypeL2Transfer, txInfo, testChainId)) }},
+		{"ComputeTxHash error", func() { testStrOrErr(ComputeTxHash(txtypes.TxTypeL2Transfer, invalid, testChainId)) }},
+		{"VerifyTxSignature", func() {
+			testString(VerifyTxSignature(txtypes.TxTypeL2Transfer, txInfo, testArg(t.Cleanup, publicKey), testChainId))
+		}},
+		{"VerifyTxSignature error", func() { testString(VerifyTxSignature(txtypes.TxTypeL2Transfer, txInfo, invalid, testChainId)) }},
+		{"GetL1SignatureBody", func() { testStrOrErr(GetL1SignatureBody(txtypes.TxTypeL2Transfer, txInfo)) }},
+		{"GetL1SignatureBody error", func() { testStrOrErr(GetL1SignatureBody(txtypes.TxTypeL2Withdraw, txInfo)) }},
+		{"ParseAuthToken", func() { testStrOrErr(ParseAuthToken(testArg(t.Cleanup, token))) }},
+		{"ParseAuthToken error", func() { testStrOrErr(ParseAuthToken(invalid)) }},
+		{"VerifyAuthToken", func() { testString(VerifyAuthToken(testArg(t.Cleanup, token), testArg(t.Cleanup, publicKey))) }},
+		{"VerifyAuthToken error", func() { testString(VerifyAuthToken(testArg(t.Cleanup, token), invalid)) }},
+		{"CreateClientHandle error", func() {
+			testHandleOrErr(CreateClientHandle(invalid, testArg(t.Cleanup, privateKey), testChainId, testApiKeyIndex, 0))
+		}},
+		{"DestroyClientHandle error", func() { testString(DestroyClientHandle(-1)) }},
+		{"CreateClient error", func() {
+			testString(CreateClient(invalid, testArg(t.Cleanup, privateKey), testChainId, testApiKeyIndex, 0))
+		}},
+		{"CheckClientHandle error", func() { testString(CheckClientHandle(h)) }},
+		{"CheckClient error", func() { testString(CheckClient(testApiKeyIndex, testAccountIndex)) }},
+		{"SwitchAPIKey error", func() { testString(SwitchAPIKey(testApiKeyIndex)) }},
+		{"SignTx", func() {
+			testLighterResult(SignTx(h, txtypes.TxTypeL2Transfer, testArg(t.Cleanup, `{"ToAccountIndex":8,"USDCAmount":1000000,"Nonce":2}`)))
+		}},
+		{"SignTx error", func() { testLighterResult(SignTx(h, txtypes.TxTypeL2Transfer, invalid)) }},
+		{"SignChangePubKeyWithHandle", func() { testStrOrErr(SignChangePubKeyWithHandle(h, testArg(t.Cleanup, publicKey), 1)) }},
+		{"SignCreateOrderWithHandle", func() { testStrOrErr(SignCreateOrderWithHandle(h, 0, 1, 1000, 1000, 0, 0, 1, 0, 0, -1, 1)) }},
+		{"SignCancelOrderWithHandle", func() { testStrOrErr(SignCancelOrderWithHandle(h, 0, 1, 1)) }},
+		{"SignWithdrawWithHandle", func() { testStrOrErr(SignWithdrawWithHandle(h, 1000000, 1)) }},
+		{"SignCreateSubAccountWithHandle", func() { testStrOrErr(SignCreateSubAccountWithHandle(h, 1)) }},
+		{"SignCancelAllOrdersWithHandle", func() { testStrOrErr(SignCancelAllOrdersWithHandle(h, 0, 0, 1)) }},
+		{"SignModifyOrderWithHandle", func() { testStrOrErr(SignModifyOrderWithHandle(h, 0, 1, 1000, 1000, 0, 1)) }},
+		{"SignTransferWithHandle", func() { testStrOrErr(SignTransferWithHandle(h, 8, 1000000, 0, memo, 1)) }},
+		{"SignCreatePublicPoolWithHandle", func() { testStrOrErr(SignCreatePublicPoolWithHandle(h, 1000, 1000000, 100, 1)) }},
+		{"SignUpdatePublicPoolWithHandle", func() { testStrOrErr(SignUpdatePublicPoolWithHandle(h, 100, 0, 1000, 100, 1)) }},
+		{"SignMintSharesWithHandle", func() { testStrOrErr(SignMintSharesWithHandle(h, 100, 10, 1)) }},
+		{"SignBurnSharesWithHandle", func() { testStrOrErr(SignBurnSharesWithHandle(h, 100, 10, 1)) }},
+		{"SignUpdateLeverageWithHandle", func() { testStrOrErr(SignUpdateLeverageWithHandle(h, 0, 1000, 0, 1)) }},
+		{"SignUpdateMarginWithHandle", func() { testStrOrErr(SignUpdateMarginWithHandle(h, 0, 1000000, 0, 1)) }},
+		{"CreateAuthTokenWithHandle", func() { testStrOrErr(CreateAuthTokenWithHandle(h, 0)) }},
+		// without CreateClient, the exports which don't take a handle only return an error
+		{"SignChangePubKey error", func() { testStrOrErr(SignChangePubKey(testArg(t.Cleanup, publicKey), 1)) }},
+		{"SignCreateOrder error", func() { testStrOrErr(SignCreateOrder(0, 1, 1000, 1000, 0, 0, 1, 0, 0, -1, 1)) }},
+		{"SignCancelOrder error", func() { testStrOrErr(SignCancelOrder(0, 1, 1)) }},
+		{"SignWithdraw error", func() { testStrOrErr(SignWithdraw(1000000, 1)) }},
+		{"SignCreateSubAccount error", func() { testStrOrErr(SignCreateSubAccount(1)) }},
+		{"SignCancelAllOrders error", func() { testStrOrErr(SignCancelAllOrders(0, 0, 1)) }},
+		{"SignModifyOrder error", func() { testStrOrErr(SignModifyOrder(0, 1, 1000, 1000, 0, 1)) }},
+		{"SignTransfer error", func() { testStrOrErr(SignTransfer(8, 1000000, 0, memo, 1)) }},
+		{"SignCreatePublicPool error", func() { testStrOrErr(SignCreatePublicPool(1000, 1000000, 100, 1)) }},
+		{"SignUpdatePublicPool error", func() { testStrOrErr(SignUpdatePublicPool(100, 0, 1000, 100, 1)) }},
+		{"SignMintShares error", func() { testStrOrErr(SignMintShares(100, 10, 1)) }},
+		{"SignBurnShares error", func() { testStrOrErr(SignBurnShares(100, 10, 1)) }},
+		{"SignUpdateLeverage error", func() { testStrOrErr(SignUpdateLeverage(0, 1000, 0, 1)) }},
+		{"SignUpdateMargin error", func() { testStrOrErr(SignUpdateMargin(0, 1000000, 0, 1)) }},
+		{"CreateAuthToken error", func() { testStrOrErr(CreateAuthToken(0)) }},
+	}
+	for _, c := range calls {
+		c.call()
+		if n := OutstandingAllocations(); n != 0 {
+			t.Fatalf("%s left %v allocations", c.name, n)
+		}
+	}
+}
+
+// resetClients drops every client, as if the library was just loaded
+func resetClients(t *testing.T) {
+	t.Helper()
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	clients = make(map[int64]*clientEntry)
+	legacyHandles = make(map[uint8]int64)
+	currentHandle = 0
+}
+
+// newApiKeyServer answers the API key lookups of CheckClient with publicKey
+func newApiKeyServer(t *testing.T, publicKey string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/apikeys" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"code":200,"api_keys":[{"account_index":%v,"api_key_index":%v,"public_key":%q}]}`,
+			testAccountIndex, testApiKeyIndex, strings.TrimPrefix(publicKey, "0x"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// signExport is an export returning a StrOrErr, which exists both with a handle & using the client selected by SwitchAPIKey
+type signExport struct {
+	name string
+	// sign calls the WithHandle export with valid args
+	sign func(handle int64) (string, string)
+	// invalid calls the WithHandle export with args it rejects, when there are such args
+	invalid func(handle int64) (string, string)
+	// current calls the export without handle, with the same args as sign
+	current func() (string, string)
+}
+
+func signExports(t *testing.T, publicKey string) []signExport {
+	pubKey := testArg(t.Cleanup, publicKey)
+	memo := testArg(t.Cleanup, strings.Repeat("m", 32))
+	shortMemo := testArg(t.Cleanup, "m")
+	invalid := testArg(t.Cleanup, "invalid")
+
+	return []signExport{
+		{
+			name: "SignChangePubKey",
+			sign: func(h int64) (string, string) {
+				return testStrOrErr(SignChangePubKeyWithHandle(testLongLong(h), pubKey, 1))
+			},
+			invalid: func(h int64) (string, string) {
+				return testStrOrErr(SignChangePubKeyWithHandle(testLongLong(h), invalid, 1))
+			},
+			current: func() (string, string) { return testStrOrErr(SignChangePubKey(pubKey, 1)) },
+		},
+		{
+			name: "SignCreateOrder",
+			sign: func(h int64) (string, string) {
+				return testStrOrErr(SignCreateOrderWithHandle(testLongLong(h), 0, 1, 1000, 1000, 0, 0, 1, 0, 0, -1, 1))
+			},
+			invalid: func(h int64) (string, string) {
+				return testStrOrErr(SignCreateOrderWithHandle(testLongLong(h), 0, 1, 0, 1000, 0, 0, 1, 0, 0, -1, 1))
+			},
+			current: func() (string, string) { return testStrOrErr(SignCreateOrder(0, 1, 1000, 1000, 0, 0, 1, 0, 0, -1, 1)) },
+		},
+		{
+			name: "SignCancelOrder",
+			sign: func(h int64) (string, string) {
+				return testStrOrErr(SignCancelOrderWithHandle(testLongLong(h), 0, 1, 1))
+			},
+			invalid: func(h int64) (string, string) {
+				return testStrOrErr(SignCancelOrderWithHandle(testLongLong(h), 0, -1, 1))
+			},
+			current: func() (string, string) { return testStrOrErr(SignCancelOrder(0, 1, 1)) },
+		},
+		{
+			name: "SignWithdraw",
+			sign: func(h int64) (string, string) {
+				return testStrOrErr(SignWithdrawWithHandle(testLongLong(h), 1000000, 1))
+			},
+			invalid: func(h int64) (string, string) { return testStrOrErr(SignWithdrawWithHandle(testLongLong(h), 0, 1)) },
+			current: func() (string, string) { return testStrOrErr(SignWithdraw(1000000, 1)) },
+		},
+		{
+			name: "SignCreateSubAccount",
+			sign: func(h int64) (string, string) {
+				return testStrOrErr(SignCreateSubAccountWithHandle(testLongLong(h), 1))
+			},
+			// the nonce can't be fetched without HTTP client
+			invalid: func(h int64) (string, string) {
+				return testStrOrErr(SignCreateSubAccountWithHandle(testLongLong(h), -1))
+			},
+			current: func() (string, string) { return testStrOrErr(SignCreateSubAccount(1)) },
+		},
+		{
+			name: "SignCancelAllOrders",
+			sign: func(h int64) (string, string) {
+				return testStrOrErr(SignCancelAllOrdersWithHandle(testLongLong(h), 0, 0, 1))
+			},
+			invalid: func(h int64) (string, string) {
+				return testStrOrErr(SignCancelAllOrdersWithHandle(testLongLong(h), 5, 0, 1))
+			},
+			current: func() (string, string) { return testStrOrErr(SignCancelAllOrders(0, 0, 1)) },
+		},
+		{
+			name: "SignModifyOrder",
+			sign: func(h int64) (string, string) {
+				return testStrOrErr(SignModifyOrderWithHandle(testLongLong(h), 0, 1, 1000, 1000, 0, 1))
+			},
+			invalid: func(h int64) (string, string) {
+				return testStrOrErr(SignModifyOrderWithHandle(testLongLong(h), 0, 1, 1000, 0, 0, 1))
+			},
+			current: func() (string, string) { return testStrOrErr(SignModifyOrder(0, 1, 1000, 1000, 0, 1)) },
+		},
+		{
+			name: "SignTransfer",
+			sign: func(h int64) (string, string) {
+				return testStrOrErr(SignTransferWithHandle(testLongLong(h), 8, 1000000, 0, memo, 1))
+			},
+			invalid: func(h int64) (string, string) {
+				return testStrOrErr(SignTransferWithHandle(testLongLong(h), 8, 1000000, 0, shortMemo, 1))
+			},
+			current: func() (string, string) { return testStrOrErr(SignTransfer(8, 1000000, 0, memo, 1)) },
+		},
+		{
+			name: "SignCreatePublicPool",
+			sign: func(h int64) (string, string) {
+				return testStrOrErr(SignCreatePublicPoolWithHandle(testLongLong(h), 1000, 1000000, 100, 1))
+			},
+			invalid: func(h int64) (string, string) {
+				return testStrOrErr(SignCreatePublicPoolWithHandle(testLongLong(h), -1, 1000000, 100, 1))
+			},
+			current: func() (string, string) { return testStrOrErr(SignCreatePublicPool(1000, 1000000, 100, 1)) },
+		},
+		{
+			name: "SignUpdatePublicPool",
+			sign: func(h int64) (string, string) {
+				return testStrOrErr(SignUpdatePublicPoolWithHandle(testLongLong(h), 100, 0, 1000, 100, 1))
+			},
+			invalid: func(h int64) (string, string) {
+				return testStrOrErr(SignUpdatePublicPoolWithHandle(testLongLong(h), 100, 5, 1000, 100, 1))
+			},
+			current: func() (string, string) { return testStrOrErr(SignUpdatePublicPool(100, 0, 1000, 100, 1)) },
+		},
+		{
+			name: "SignMintShares",
+			sign: func(h int64) (string, string) {
+				return testStrOrErr(SignMintSharesWithHandle(testLongLong(h), 100, 10, 1))
+			},
+			invalid: func(h int64) (string, string) {
+				return testStrOrErr(SignMintSharesWithHandle(testLongLong(h), 100, 0, 1))
+			},
+			current: func() (string, string) { return testStrOrErr(SignMintShares(100, 10, 1)) },
+		},
+		{
+			name: "SignBurnShares",
+			sign: func(h int64) (string, string) {
+				return testStrOrErr(SignBurnSharesWithHandle(testLongLong(h), 100, 10, 1))
+			},
+			invalid: func(h int64) (string, string) {
+				return testStrOrErr(SignBurnSharesWithHandle(testLongLong(h), 100, 0, 1))
+			},
+			current: func() (string, string) { return testStrOrErr(SignBurnShares(100, 10, 1)) },
+		},
+		{
+			name: "SignUpdateLeverage",
+			sign: func(h int64) (string, string) {
+				return testStrOrErr(SignUpdateLeverageWithHandle(testLongLong(h), 0, 1000, 0, 1))
+			},
+			invalid: func(h int64) (string, string) {
+				return testStrOrErr(SignUpdateLeverageWithHandle(testLongLong(h), 0, 1000, 5, 1))
+			},
+			current: func() (string, string) { return testStrOrErr(SignUpdateLeverage(0, 1000, 0, 1)) },
+		},
+		{
+			name: "SignUpdateMargin",
+			sign: func(h int64) (string, string) {
+				return testStrOrErr(SignUpdateMarginWithHandle(testLongLong(h), 0, 1000000, 0, 1))
+			},
+			invalid: func(h int64) (string, string) {
+				return testStrOrErr(SignUpdateMarginWithHandle(testLongLong(h), 0, 1000000, 5, 1))
+			},
+			current: func() (string, string) { return testStrOrErr(SignUpdateMargin(0, 1000000, 0, 1)) },
+		},
+		{
+			// any deadline is signed, so only the handle can be invalid
+			name:    "CreateAuthToken",
+			sign:    func(h int64) (string, string) { return testStrOrErr(CreateAuthTokenWithHandle(testLongLong(h), 0)) },
+			current: func() (string, string) { return testStrOrErr(CreateAuthToken(0)) },
+		},
+	}
+}
+
+func TestSignExports(t *testing.T) {
+	resetClients(t)
+	handle, privateKey, publicKey := newTestHandle(t)
+	exports := signExports(t, publicKey)
+
+	for _, e := range exports {
+		t.Run(e.name, func(t *testing.T) {
+			if str, errStr := e.sign(handle); errStr != "" || str == "" {
+				t.Fatalf("sign returned %q, err %q", str, errStr)
+			}
+			if e.invalid != nil {
+				if str, errStr := e.invalid(handle); errStr == "" || str != "" {
+					t.Fatalf("invalid args returned %q, err %q", str, errStr)
+				}
+			}
+			if _, errStr := e.sign(0); errStr != errClientNotCreated.Error() {
+				t.Fatalf("handle 0 returned err %q", errStr)
+			}
+			if _, errStr := e.sign(handle + 1000); !strings.HasPrefix(errStr, errInvalidHandle.Error()) {
+				t.Fatalf("unknown handle returned err %q", errStr)
+			}
+			if _, errStr := e.current(); errStr != errClientNotCreated.Error() {
+				t.Fatalf("without CreateClient returned err %q", errStr)
+			}
+		})
+	}
+
+	if errStr := testString(CreateClient(testArg(t.Cleanup, ""), testArg(t.Cleanup, privateKey), testChainId, testApiKeyIndex, testAccountIndex)); errStr != "" {
+		t.Fatal(errStr)
+	}
+	t.Cleanup(func() { resetClients(t) })
+	for _, e := range exports {
+		t.Run(e.name+" after CreateClient", func(t *testing.T) {
+			str, errStr := e.current()
+			if errStr != "" || str == "" {
+				t.Fatalf("returned %q, err %q", str, errStr)
+			}
+		})
+	}
+}
+
+func TestSignTx(t *testing.T) {
+	resetClients(t)
+	handle, _, _ := newTestHandle(t)
+	h := testLongLong(handle)
+
+	result := testLighterResult(SignTx(h, txtypes.TxTypeL2Transfer, testArg(t.Cleanup, `{"ToAccountIndex":8,"USDCAmount":1000000,"Nonce":1}`)))
+	if result.err != "" || result.errCode != 0 {
+		t.Fatalf("errCode %v, err %q", result.errCode, result.err)
+	}
+	if result.version != testResultVersion || result.txInfo == "" || result.txHash == "" || result.messageToSign == "" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	tests := []struct {
+		name    string
+		handle  int64
+		txType  int
+		request string
+		errCode int
+	}{
+		{"handle 0", 0, txtypes.TxTypeL2Withdraw, `{"USDCAmount":1000000,"Nonce":1}`, testErrInvalidHandle},
+		{"unknown handle", handle + 1000, txtypes.TxTypeL2Withdraw, `{"USDCAmount":1000000,"Nonce":1}`, testErrInvalidHandle},
+		{"unknown tx type", handle, 255, `{}`, testErrInvalidRequest},
+		{"invalid JSON", handle, txtypes.TxTypeL2Withdraw, `invalid`, testErrInvalidRequest},
+		{"invalid tx", handle, txtypes.TxTypeL2Withdraw, `{"USDCAmount":0,"Nonce":1}`, testErrTx},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := testLighterResult(SignTx(testLongLong(tt.handle), testInt(tt.txType), testArg(t.Cleanup, tt.request)))
+			if result.errCode != tt.errCode || result.err == "" || result.txInfo != "" {
+				t.Fatalf("expected errCode %v, got %+v", tt.errCode, result)
+			}
+		})
+	}
+}
+
+func TestClientExports(t *testing.T) {
+	resetClients(t)
+	t.Cleanup(func() { resetClients(t) })
+	privateKey, publicKey, errStr := testApiKeyResponse(GenerateAPIKey(testArg(t.Cleanup, "")))
+	if errStr != "" {
+		t.Fatal(errStr)
+	}
+	if _, _, errStr := testApiKeyResponse(GenerateAPIKey(testArg(t.Cleanup, "seed"))); errStr != "" {
+		t.Fatal(errStr)
+	}
+	server := newApiKeyServer(t, publicKey)
+	url := testArg(t.Cleanup, server.URL)
+	cPrivateKey := testArg(t.Cleanup, privateKey)
+
+	// nothing is created yet
+	if errStr := testString(CheckClientHandle(0)); errStr != errClientNotCreated.Error() {
+		t.Fatalf("CheckClientHandle without client returned %q", errStr)
+	}
+	if errStr := testString(CheckClient(testApiKeyIndex, testAccountIndex)); errStr == "" {
+		t.Fatal("CheckClient without client succeeded")
+	}
+	if errStr := testString(SwitchAPIKey(testApiKeyIndex)); errStr == "" {
+		t.Fatal("SwitchAPIKey without client succeeded")
+	}
+
+	if _, errStr := testHandleOrErr(CreateClientHandle(url, testArg(t.Cleanup, "invalid"), testChainId, testApiKeyIndex, testAccountIndex)); errStr == "" {
+		t.Fatal("CreateClientHandle with invalid key succeeded")
+	}
+	handle, errStr := testHandleOrErr(CreateClientHandle(url, cPrivateKey, testChainId, testApiKeyIndex, testAccountIndex))
+	if errStr != "" {
+		t.Fatal(errStr)
+	}
+	if errStr := testString(CheckClientHandle(testLongLong(handle))); errStr != "" {
+		t.Fatalf("CheckClientHandle returned %q", errStr)
+	}
+	if errStr := testString(DestroyClientHandle(testLongLong(handle))); errStr != "" {
+		t.Fatalf("DestroyClientHandle returned %q", errStr)
+	}
+	if errStr := testString(DestroyClientHandle(testLongLong(handle))); errStr == "" {
+		t.Fatal("DestroyClientHandle of a destroyed handle succeeded")
+	}
+	if errStr := testString(CheckClientHandle(testLongLong(handle))); !strings.HasPrefix(errStr, errInvalidHandle.Error()) {
+		t.Fatalf("CheckClientHandle of a destroyed handle returned %q", errStr)
+	}
+
+	if errStr := testString(CreateClient(url, cPrivateKey, testChainId, testApiKeyIndex, 0)); errStr == "" {
+		t.Fatal("CreateClient with account index 0 succeeded")
+	}
+	if errStr := testString(CreateClient(url, cPrivateKey, testChainId, testApiKeyIndex, testAccountIndex)); errStr != "" {
+		t.Fatalf("CreateClient returned %q", errStr)
+	}
+	if errStr := testString(CheckClient(testApiKeyIndex, testAccountIndex)); errStr != "" {
+		t.Fatalf("CheckClient returned %q", errStr)
+	}
+	if errStr := testString(CheckClient(testApiKeyIndex, testAccountIndex+1)); errStr == "" {
+		t.Fatal("CheckClient of another account succeeded")
+	}
+	if errStr := testString(SwitchAPIKey(testApiKeyIndex)); errStr != "" {
+		t.Fatalf("SwitchAPIKey returned %q", errStr)
+	}
+	if errStr := testString(SwitchAPIKey(testApiKeyIndex + 1)); errStr == "" {
+		t.Fatal("SwitchAPIKey to an unknown api key succeeded")
+	}
+
+	// a client whose key isn't the one registered on Lighter
+	otherKey, _, _ := testApiKeyResponse(GenerateAPIKey(testArg(t.Cleanup, "")))
+	other, errStr := testHandleOrErr(CreateClientHandle(url, testArg(t.Cleanup, otherKey), testChainId, testApiKeyIndex, testAccountIndex))
+	if errStr != "" {
+		t.Fatal(errStr)
+	}
+	if errStr := testString(CheckClientHandle(testLongLong(other))); errStr == "" {
+		t.Fatal("CheckClientHandle with a mismatching key succeeded")
+	}
+}
+
+// TestSignThenVerify checks the utility exports against the txs & auth tokens signed by the library itself
+func TestSignThenVerify(t *testing.T) {
+	resetClients(t)
+	handle, privateKey, publicKey := newTestHandle(t)
+	h := testLongLong(handle)
+	cPublicKey := testArg(t.Cleanup, publicKey)
+
+	derived, errStr := testStrOrErr(DerivePublicKey(testArg(t.Cleanup, privateKey)))
+	if errStr != "" || derived != publicKey {
+		t.Fatalf("derived public key %q, err %q, expected %q", derived, errStr, publicKey)
+	}
+	if _, errStr := testStrOrErr(DerivePublicKey(testArg(t.Cleanup, "0x00"))); errStr == "" {
+		t.Fatal("derived the public key of a short private key")
+	}
+
+	transfer := testLighterResult(SignTx(h, txtypes.TxTypeL2Transfer, testArg(t.Cleanup, `{"ToAccountIndex":8,"USDCAmount":1000000,"Nonce":1}`)))
+	if transfer.err != "" {
+		t.Fatal(transfer.err)
+	}
+	txInfo := testArg(t.Cleanup, transfer.txInfo)
+
+	hash, errStr := testStrOrErr(ComputeTxHash(txtypes.TxTypeL2Transfer, txInfo, testChainId))
+	if errStr != "" || hash != transfer.txHash {
+		t.Fatalf("computed hash %q, err %q, expected %q", hash, errStr, transfer.txHash)
+	}
+	if hash, _ := testStrOrErr(ComputeTxHash(txtypes.TxTypeL2Transfer, txInfo, testChainId+1)); hash == transfer.txHash {
+		t.Fatal("computed the same hash on another chain")
+	}
+	body, errStr := testStrOrErr(GetL1SignatureBody(txtypes.TxTypeL2Transfer, txInfo))
+	if errStr != "" || body != transfer.messageToSign {
+		t.Fatalf("L1 signature body %q, err %q, expected %q", body, errStr, transfer.messageToSign)
+	}
+
+	if errStr := testString(VerifyTxSignature(txtypes.TxTypeL2Transfer, txInfo, cPublicKey, testChainId)); errStr != "" {
+		t.Fatalf("signature is invalid: %s", errStr)
+	}
+	_, otherPublicKey, _ := testApiKeyResponse(GenerateAPIKey(testArg(t.Cleanup, "")))
+	if errStr := testString(VerifyTxSignature(txtypes.TxTypeL2Transfer, txInfo, testArg(t.Cleanup, otherPublicKey), testChainId)); errStr == "" {
+		t.Fatal("verified with another key")
+	}
+	tampered := testArg(t.Cleanup, strings.Replace(transfer.txInfo, `"USDCAmount":1000000`, `"USDCAmount":2000000`, 1))
+	if errStr := testString(VerifyTxSignature(txtypes.TxTypeL2Transfer, tampered, cPublicKey, testChainId)); errStr == "" {
+		t.Fatal("verified a tampered tx")
+	}
+	unsigned := testArg(t.Cleanup, `{"FromAccountIndex":7,"ApiKeyIndex":3,"ToAccountIndex":8,"USDCAmount":1000000,"Nonce":1}`)
+	if errStr := testString(VerifyTxSignature(txtypes.TxTypeL2Transfer, unsigned, cPublicKey, testChainId)); errStr == "" {
+		t.Fatal("verified an unsigned tx")
+	}
+
+	deadline := time.Now().Add(time.Hour).Unix()
+	token, errStr := testStrOrErr(CreateAuthTokenWithHandle(h, testLongLong(deadline)))
+	if errStr != "" {
+		t.Fatal(errStr)
+	}
+	cToken := testArg(t.Cleanup, token)
+	fields, errStr := testStrOrErr(ParseAuthToken(cToken))
+	if errStr != "" {
+		t.Fatal(errStr)
+	}
+	if expected := fmt.Sprintf(`{"account_index":%v,"api_key_index":%v,"deadline":%v}`, testAccountIndex, testApiKeyIndex, deadline); fields != expected {
+		t.Fatalf("parsed %s, expected %s", fields, expected)
+	}
+	if errStr := testString(VerifyAuthToken(cToken, cPublicKey)); errStr != "" {
+		t.Fatalf("auth token is invalid: %s", errStr)
+	}
+	if errStr := testString(VerifyAuthToken(cToken, testArg(t.Cleanup, otherPublicKey))); errStr == "" {
+		t.Fatal("verified an auth token with another key")
+	}
+	expired, _ := testStrOrErr(CreateAuthTokenWithHandle(h, testLongLong(time.Now().Add(-time.Minute).Unix())))
+	if errStr := testString(VerifyAuthToken(testArg(t.Cleanup, expired), cPublicKey)); errStr == "" {
+		t.Fatal("verified an expired auth token")
+	}
+}
@@ -0,0 +1,89 @@
+//go:build sharedlibtest
+
+package main
+
+/*
+#include <stdlib.h>
+#include "lighter.h"
+*/
+import "C"
+
+import "unsafe"
+
+// The helpers below let the tests, which can't use cgo themselves, build the arguments of the exports & read their results.
+// They're only built with the sharedlibtest tag, so they don't ship in the library: run go test -tags sharedlibtest ./sharedlib.
+// Arguments are allocated outside of the allocations counter, as the library never frees them.
+
+// testArg allocates a char* argument, freed by cleanup, e.g. testing.T.Cleanup
+func testArg(cleanup func(func()), s string) *C.char {
+	cs := C.CString(s)
+	cleanup(func() {
+		C.free(unsafe.Pointer(cs))
+	})
+	return cs
+}
+
+// the LighterResult constants of lighter.h, which the tests can't read themselves
+const (
+	testResultVersion     = C.LIGHTER_RESULT_VERSION
+	testErrInvalidHandle  = C.LIGHTER_ERR_INVALID_HANDLE
+	testErrInvalidRequest = C.LIGHTER_ERR_INVALID_REQUEST
+	testErrTx             = C.LIGHTER_ERR_TX
+)
+
+func testInt(v int) C.int {
+	return C.int(v)
+}
+
+func testLongLong(v int64) C.longlong {
+	return C.longlong(v)
+}
+
+// testString reads & frees a char* returned by the library, "" standing for NULL
+func testString(s *C.char) string {
+	if s == nil {
+		return ""
+	}
+	defer FreeString(s)
+	return C.GoString(s)
+}
+
+// testStrOrErr reads & frees a StrOrErr returned by the library
+func testStrOrErr(result C.StrOrErr) (string, string) {
+	defer FreeStrOrErr(result)
+	return C.GoString(result.str), C.GoString(result.err)
+}
+
+// testApiKeyResponse reads & frees an ApiKeyResponse returned by the library
+func testApiKeyResponse(result C.ApiKeyResponse) (string, string, string) {
+	defer FreeApiKeyResponse(result)
+	return C.GoString(result.privateKey), C.GoString(result.publicKey), C.GoString(result.err)
+}
+
+// testHandleOrErr reads & frees a HandleOrErr returned by the library
+func testHandleOrErr(result C.HandleOrErr) (int64, string) {
+	defer FreeHandleOrErr(result)
+	return int64(result.handle), C.GoString(result.err)
+}
+
+type testResult struct {
+	version       int
+	errCode       int
+	txInfo        string
+	txHash        string
+	messageToSign string
+	err           string
+}
+
+// testLighterResult reads & frees a LighterResult returned by the library
+func testLighterResult(result C.LighterResult) *testResult {
+	defer FreeLighterResult(result)
+	return &testResult{
+		version:       int(result.version),
+		errCode:       int(result.errCode),
+		txInfo:        C.GoString(result.txInfo),
+		txHash:        C.GoString(result.txHash),
+		messageToSign: C.GoString(result.messageToSign),
+		err:           C.GoString(result.err),
+	}
+}
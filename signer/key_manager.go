@@ -31,6 +31,15 @@ func NewKeyManager(b []byte) (KeyManager, error) {
 	return &keyManager{key: curve.ScalarElementFromLittleEndianBytes(b)}, nil
 }
 
+// GenerateKeyManager returns a new key derived from seed, or a random one when seed is empty
+func GenerateKeyManager(seed string) KeyManager {
+	var seedP *string
+	if seed != "" {
+		seedP = &seed
+	}
+	return &keyManager{key: curve.SampleScalar(seedP)}
+}
+
 func (key *keyManager) Sign(hashedMessage []byte, hFunc hash.Hash) ([]byte, error) {
 	hashedMessageAsQuinticExtension, err := gFp5.FromCanonicalLittleEndianBytes(hashedMessage)
 	if err != nil {
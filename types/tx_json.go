@@ -0,0 +1,174 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/uncle-gua/lighter-go/signer"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+var ErrInvalidTxRequest = errors.New("invalid tx request")
+
+// TxRequest is a tx request given as JSON, decoded by DecodeTxRequest
+type TxRequest struct {
+	TxType uint8
+	// Req is the *TxReq matching TxType, e.g. *CreateOrderTxReq for TxTypeL2CreateOrder. It's nil for CreateSubAccount, which has no field.
+	Req any
+	// Nonce & ExpiredAt are the optional TransactOpts set next to the request fields
+	Nonce     *int64
+	ExpiredAt int64
+}
+
+// DecodeTxRequest decodes the JSON request of a tx of txType, with the fields of the matching *TxReq and the optional
+// Nonce & ExpiredAt. The fixed size byte arrays, ChangePubKey's PubKey & Transfer's Memo, are 0x prefixed hex strings.
+func DecodeTxRequest(txType uint8, request []byte) (*TxRequest, error) {
+	ret := &TxRequest{TxType: txType}
+	opts := &struct {
+		Nonce     *int64
+		ExpiredAt int64
+	}{}
+	if err := decodeTxRequest(request, opts); err != nil {
+		return nil, err
+	}
+	ret.Nonce, ret.ExpiredAt = opts.Nonce, opts.ExpiredAt
+
+	switch txType {
+	case txtypes.TxTypeL2ChangePubKey:
+		req := &struct{ PubKey string }{}
+		if err := decodeTxRequest(request, req); err != nil {
+			return nil, err
+		}
+		pubKey, err := DecodeHex(req.PubKey, 40)
+		if err != nil {
+			return nil, fmt.Errorf("%w. invalid PubKey. err: %w", ErrInvalidTxRequest, err)
+		}
+		tx := &ChangePubKeyReq{}
+		copy(tx.PubKey[:], pubKey)
+		ret.Req = tx
+		return ret, nil
+	case txtypes.TxTypeL2CreateSubAccount:
+		return ret, nil
+	case txtypes.TxTypeL2Transfer:
+		req := &struct {
+			ToAccountIndex int64
+			USDCAmount     int64
+			Fee            int64
+			Memo           string
+		}{}
+		if err := decodeTxRequest(request, req); err != nil {
+			return nil, err
+		}
+		tx := &TransferTxReq{ToAccountIndex: req.ToAccountIndex, USDCAmount: req.USDCAmount, Fee: req.Fee}
+		if req.Memo != "" {
+			memo, err := DecodeHex(req.Memo, 32)
+			if err != nil {
+				return nil, fmt.Errorf("%w. invalid Memo. err: %w", ErrInvalidTxRequest, err)
+			}
+			copy(tx.Memo[:], memo)
+		}
+		ret.Req = tx
+		return ret, nil
+	case txtypes.TxTypeL2CreatePublicPool:
+		ret.Req = &CreatePublicPoolTxReq{}
+	case txtypes.TxTypeL2UpdatePublicPool:
+		ret.Req = &UpdatePublicPoolTxReq{}
+	case txtypes.TxTypeL2Withdraw:
+		ret.Req = &WithdrawTxReq{}
+	case txtypes.TxTypeL2CreateOrder:
+		ret.Req = &CreateOrderTxReq{}
+	case txtypes.TxTypeL2CreateGroupedOrders:
+		ret.Req = &CreateGroupedOrdersTxReq{}
+	case txtypes.TxTypeL2CancelOrder:
+		ret.Req = &CancelOrderTxReq{}
+	case txtypes.TxTypeL2CancelAllOrders:
+		ret.Req = &CancelAllOrdersTxReq{}
+	case txtypes.TxTypeL2ModifyOrder:
+		ret.Req = &ModifyOrderTxReq{}
+	case txtypes.TxTypeL2MintShares:
+		ret.Req = &MintSharesTxReq{}
+	case txtypes.TxTypeL2BurnShares:
+		ret.Req = &BurnSharesTxReq{}
+	case txtypes.TxTypeL2UpdateLeverage:
+		ret.Req = &UpdateLeverageTxReq{}
+	case txtypes.TxTypeL2UpdateMargin:
+		ret.Req = &UpdateMarginTxReq{}
+	default:
+		return nil, fmt.Errorf("%w. unsupported tx type %v", ErrInvalidTxRequest, txType)
+	}
+	if err := decodeTxRequest(request, ret.Req); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ConstructTx signs the request with the Construct*Tx function of its tx type
+func ConstructTx(key signer.Signer, chainId uint32, req *TxRequest, ops *TransactOpts) (txtypes.TxInfo, error) {
+	if req.TxType == txtypes.TxTypeL2CreateSubAccount {
+		return ConstructCreateSubAccountTx(key, chainId, ops)
+	}
+
+	var tx txtypes.TxInfo
+	var err error
+	switch r := req.Req.(type) {
+	case *ChangePubKeyReq:
+		tx, err = ConstructChangePubKeyTx(key, chainId, r, ops)
+	case *CreatePublicPoolTxReq:
+		tx, err = ConstructCreatePublicPoolTx(key, chainId, r, ops)
+	case *UpdatePublicPoolTxReq:
+		tx, err = ConstructUpdatePublicPoolTx(key, chainId, r, ops)
+	case *TransferTxReq:
+		tx, err = ConstructTransferTx(key, chainId, r, ops)
+	case *WithdrawTxReq:
+		tx, err = ConstructWithdrawTx(key, chainId, r, ops)
+	case *CreateOrderTxReq:
+		tx, err = ConstructCreateOrderTx(key, chainId, r, ops)
+	case *CreateGroupedOrdersTxReq:
+		tx, err = ConstructL2CreateGroupedOrdersTx(key, chainId, r, ops)
+	case *CancelOrderTxReq:
+		tx, err = ConstructL2CancelOrderTx(key, chainId, r, ops)
+	case *CancelAllOrdersTxReq:
+		tx, err = ConstructL2CancelAllOrdersTx(key, chainId, r, ops)
+	case *ModifyOrderTxReq:
+		tx, err = ConstructL2ModifyOrderTx(key, chainId, r, ops)
+	case *MintSharesTxReq:
+		tx, err = ConstructMintSharesTx(key, chainId, r, ops)
+	case *BurnSharesTxReq:
+		tx, err = ConstructBurnSharesTx(key, chainId, r, ops)
+	case *UpdateLeverageTxReq:
+		tx, err = ConstructUpdateLeverageTx(key, chainId, r, ops)
+	case *UpdateMarginTxReq:
+		tx, err = ConstructUpdateMarginTx(key, chainId, r, ops)
+	default:
+		return nil, fmt.Errorf("%w. unsupported tx type %v", ErrInvalidTxRequest, req.TxType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func decodeTxRequest(request []byte, tx any) error {
+	if err := json.Unmarshal(request, tx); err != nil {
+		return fmt.Errorf("%w. failed to parse JSON. err: %w", ErrInvalidTxRequest, err)
+	}
+	return nil
+}
+
+// DecodeHex decodes a 0x prefixed or bare hex string of size bytes
+func DecodeHex(s string, size int) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") {
+		s = "0x" + s
+	}
+	b, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != size {
+		return nil, fmt.Errorf("expected %v bytes but got %v", size, len(b))
+	}
+	return b, nil
+}
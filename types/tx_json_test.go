@@ -0,0 +1,62 @@
+package types
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+func TestDecodeTxRequest(t *testing.T) {
+	pubKey := "0x" + strings.Repeat("ab", 40)
+	tests := []struct {
+		name    string
+		txType  uint8
+		request string
+		err     bool
+	}{
+		{name: "order", txType: txtypes.TxTypeL2CreateOrder, request: `{"MarketIndex":1,"BaseAmount":10,"Price":100,"Nonce":5,"ExpiredAt":1900000000000}`},
+		{name: "change pub key", txType: txtypes.TxTypeL2ChangePubKey, request: `{"PubKey":"` + pubKey + `"}`},
+		{name: "short pub key", txType: txtypes.TxTypeL2ChangePubKey, request: `{"PubKey":"0xabcd"}`, err: true},
+		{name: "transfer", txType: txtypes.TxTypeL2Transfer, request: `{"ToAccountIndex":8,"USDCAmount":1000,"Memo":"` + strings.Repeat("01", 32) + `"}`},
+		{name: "transfer without memo", txType: txtypes.TxTypeL2Transfer, request: `{"ToAccountIndex":8,"USDCAmount":1000}`},
+		{name: "bad memo", txType: txtypes.TxTypeL2Transfer, request: `{"Memo":"0xzz"}`, err: true},
+		{name: "sub account", txType: txtypes.TxTypeL2CreateSubAccount, request: `{}`},
+		{name: "invalid JSON", txType: txtypes.TxTypeL2CreateOrder, request: `{`, err: true},
+		{name: "unsupported", txType: 255, request: `{}`, err: true},
+	}
+	for _, tt := range tests {
+		req, err := DecodeTxRequest(tt.txType, []byte(tt.request))
+		if tt.err {
+			if !errors.Is(err, ErrInvalidTxRequest) {
+				t.Fatalf("%s: expected ErrInvalidTxRequest, got %v", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		// the decoded request signs into a tx of the same type
+		tx, err := ConstructTx(newTestKey(t), testChainId, req, newTestOpts())
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if tx.GetTxType() != tt.txType {
+			t.Fatalf("%s: expected tx type %v, got %v", tt.name, tt.txType, tx.GetTxType())
+		}
+	}
+
+	req, err := DecodeTxRequest(txtypes.TxTypeL2CreateOrder, []byte(tests[0].request))
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, ok := req.Req.(*CreateOrderTxReq)
+	if !ok || order.MarketIndex != 1 || order.BaseAmount != 10 || order.Price != 100 {
+		t.Fatalf("unexpected request %+v", req.Req)
+	}
+	if req.Nonce == nil || *req.Nonce != 5 || req.ExpiredAt != 1900000000000 {
+		t.Fatalf("unexpected opts %v %v", req.Nonce, req.ExpiredAt)
+	}
+}
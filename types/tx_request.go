@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"time"
 
-	g "github.com/elliottech/poseidon_crypto/field/goldilocks"
 	gFp5 "github.com/elliottech/poseidon_crypto/field/goldilocks_quintic_extension"
 	p2 "github.com/elliottech/poseidon_crypto/hash/poseidon2_goldilocks"
 	ethCommon "github.com/ethereum/go-ethereum/common"
@@ -117,13 +116,11 @@ func ConstructAuthToken(key signer.Signer, deadline time.Time, ops *TransactOpts
 	}
 	message := fmt.Sprintf("%v:%v:%v", deadline.Unix(), *ops.FromAccountIndex, *ops.ApiKeyIndex)
 
-	msgInField, err := g.ArrayFromCanonicalLittleEndianBytes([]byte(message))
+	msgHash, err := authTokenHash(message)
 	if err != nil {
-		return "", fmt.Errorf("failed to convert bytes to field element. message: %s, error: %w", message, err)
+		return "", err
 	}
 
-	msgHash := p2.HashToQuinticExtension(msgInField).ToLittleEndianBytes()
-
 	signatureBytes, err := key.Sign(msgHash, p2.NewPoseidon2())
 	if err != nil {
 		return "", err
@@ -151,6 +148,7 @@ func ConstructChangePubKeyTx(key signer.Signer, lighterChainId uint32, tx *Chang
 	}
 
 	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.DryRun = ops.DryRun
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -173,6 +171,7 @@ func ConstructCreateSubAccountTx(key signer.Signer, lighterChainId uint32, ops *
 	}
 
 	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.DryRun = ops.DryRun
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -195,6 +194,7 @@ func ConstructCreatePublicPoolTx(key signer.Signer, lighterChainId uint32, tx *C
 	}
 
 	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.DryRun = ops.DryRun
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -217,6 +217,7 @@ func ConstructUpdatePublicPoolTx(key signer.Signer, lighterChainId uint32, tx *U
 	}
 
 	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.DryRun = ops.DryRun
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -239,6 +240,7 @@ func ConstructWithdrawTx(key signer.Signer, lighterChainId uint32, tx *WithdrawT
 	}
 
 	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.DryRun = ops.DryRun
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -261,6 +263,7 @@ func ConstructCreateOrderTx(key signer.Signer, lighterChainId uint32, tx *Create
 	}
 
 	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.DryRun = ops.DryRun
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -283,6 +286,7 @@ func ConstructL2CreateGroupedOrdersTx(key signer.Signer, lighterChainId uint32,
 	}
 
 	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.DryRun = ops.DryRun
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -305,6 +309,7 @@ func ConstructL2CancelOrderTx(key signer.Signer, lighterChainId uint32, tx *Canc
 	}
 
 	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.DryRun = ops.DryRun
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -327,6 +332,7 @@ func ConstructL2ModifyOrderTx(key signer.Signer, lighterChainId uint32, tx *Modi
 	}
 
 	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.DryRun = ops.DryRun
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -349,6 +355,7 @@ func ConstructL2CancelAllOrdersTx(key signer.Signer, lighterChainId uint32, tx *
 	}
 
 	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.DryRun = ops.DryRun
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -371,6 +378,7 @@ func ConstructTransferTx(key signer.Signer, lighterChainId uint32, tx *TransferT
 	}
 
 	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.DryRun = ops.DryRun
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -393,6 +401,7 @@ func ConstructMintSharesTx(key signer.Signer, lighterChainId uint32, tx *MintSha
 	}
 
 	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.DryRun = ops.DryRun
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -415,6 +424,7 @@ func ConstructBurnSharesTx(key signer.Signer, lighterChainId uint32, tx *BurnSha
 	}
 
 	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.DryRun = ops.DryRun
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -437,6 +447,7 @@ func ConstructUpdateLeverageTx(key signer.Signer, lighterChainId uint32, tx *Upd
 	}
 
 	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.DryRun = ops.DryRun
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -459,6 +470,7 @@ func ConstructUpdateMarginTx(key signer.Signer, lighterChainId uint32, tx *Updat
 	}
 
 	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.DryRun = ops.DryRun
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -641,6 +653,7 @@ func ConvertUpdateLeverageTx(tx *UpdateLeverageTxReq, ops *TransactOpts) *txtype
 		ApiKeyIndex:           *ops.ApiKeyIndex,
 		MarketIndex:           tx.MarketIndex,
 		InitialMarginFraction: tx.InitialMarginFraction,
+		MarginMode:            tx.MarginMode,
 		ExpiredAt:             ops.ExpiredAt,
 		Nonce:                 *ops.Nonce,
 	}
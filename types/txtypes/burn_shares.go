@@ -18,6 +18,7 @@ type L2BurnSharesTxInfo struct {
 	Nonce      int64
 	Sig        []byte
 	SignedHash string `json:"-"`
+	DryRun     bool   `json:"-"`
 }
 
 func (txInfo *L2BurnSharesTxInfo) GetTxType() uint8 {
@@ -32,6 +33,10 @@ func (txInfo *L2BurnSharesTxInfo) GetTxHash() string {
 	return txInfo.SignedHash
 }
 
+func (txInfo *L2BurnSharesTxInfo) IsDryRun() bool {
+	return txInfo.DryRun
+}
+
 func (txInfo *L2BurnSharesTxInfo) Validate() error {
 	if txInfo.AccountIndex < MinAccountIndex {
 		return ErrFromAccountIndexTooLow
@@ -18,6 +18,7 @@ type L2CancelOrderTxInfo struct {
 	Nonce      int64
 	Sig        []byte
 	SignedHash string `json:"-"`
+	DryRun     bool   `json:"-"`
 }
 
 func (txInfo *L2CancelOrderTxInfo) GetTxType() uint8 {
@@ -32,6 +33,10 @@ func (txInfo *L2CancelOrderTxInfo) GetTxHash() string {
 	return txInfo.SignedHash
 }
 
+func (txInfo *L2CancelOrderTxInfo) IsDryRun() bool {
+	return txInfo.DryRun
+}
+
 func (txInfo *L2CancelOrderTxInfo) Validate() error {
 	// AccountIndex
 	if txInfo.AccountIndex < MinAccountIndex {
@@ -44,6 +44,7 @@ type L2ChangePubKeyTxInfo struct {
 	Nonce      int64
 	Sig        []byte
 	SignedHash string `json:"-"`
+	DryRun     bool   `json:"-"`
 }
 
 func (txInfo *L2ChangePubKeyTxInfo) GetTxType() uint8 {
@@ -58,6 +59,10 @@ func (txInfo *L2ChangePubKeyTxInfo) GetTxHash() string {
 	return txInfo.SignedHash
 }
 
+func (txInfo *L2ChangePubKeyTxInfo) IsDryRun() bool {
+	return txInfo.DryRun
+}
+
 func (txInfo *L2ChangePubKeyTxInfo) Validate() error {
 	// AccountIndex
 	if txInfo.AccountIndex < MinAccountIndex {
@@ -20,6 +20,7 @@ type L2CreateGroupedOrdersTxInfo struct {
 	Nonce      int64
 	Sig        []byte
 	SignedHash string `json:"-"`
+	DryRun     bool   `json:"-"`
 }
 
 func (txInfo *L2CreateGroupedOrdersTxInfo) GetTxType() uint8 {
@@ -34,6 +35,10 @@ func (txInfo *L2CreateGroupedOrdersTxInfo) GetTxHash() string {
 	return txInfo.SignedHash
 }
 
+func (txInfo *L2CreateGroupedOrdersTxInfo) IsDryRun() bool {
+	return txInfo.DryRun
+}
+
 func (txInfo *L2CreateGroupedOrdersTxInfo) Validate() error {
 	// AccountIndex
 	if txInfo.AccountIndex < MinAccountIndex {
@@ -15,6 +15,7 @@ type L2CreateSubAccountTxInfo struct {
 	Nonce      int64
 	Sig        []byte
 	SignedHash string `json:"-"`
+	DryRun     bool   `json:"-"`
 }
 
 func (txInfo *L2CreateSubAccountTxInfo) GetTxType() uint8 {
@@ -29,6 +30,10 @@ func (txInfo *L2CreateSubAccountTxInfo) GetTxHash() string {
 	return txInfo.SignedHash
 }
 
+func (txInfo *L2CreateSubAccountTxInfo) IsDryRun() bool {
+	return txInfo.DryRun
+}
+
 func (txInfo *L2CreateSubAccountTxInfo) Validate() error {
 	// AccountIndex
 	if txInfo.AccountIndex < MinAccountIndex {
@@ -15,6 +15,9 @@ type TxInfo interface {
 	// Returns empty string if the Tx is not signed.
 	GetTxHash() string
 
+	// IsDryRun reports whether the tx was signed with TransactOpts.DryRun, in which case it must never be sent to Lighter
+	IsDryRun() bool
+
 	Validate() error
 
 	Hash(lighterChainId uint32, extra ...g.Element) (msgHash []byte, err error)
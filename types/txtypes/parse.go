@@ -0,0 +1,50 @@
+package txtypes
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ParseTxInfo decodes the tx_info JSON of a tx of txType, as returned by GetTxInfo
+func ParseTxInfo(txType uint8, txInfo []byte) (TxInfo, error) {
+	var tx TxInfo
+	switch txType {
+	case TxTypeL2ChangePubKey:
+		tx = &L2ChangePubKeyTxInfo{}
+	case TxTypeL2CreateSubAccount:
+		tx = &L2CreateSubAccountTxInfo{}
+	case TxTypeL2CreatePublicPool:
+		tx = &L2CreatePublicPoolTxInfo{}
+	case TxTypeL2UpdatePublicPool:
+		tx = &L2UpdatePublicPoolTxInfo{}
+	case TxTypeL2Transfer:
+		tx = &L2TransferTxInfo{}
+	case TxTypeL2Withdraw:
+		tx = &L2WithdrawTxInfo{}
+	case TxTypeL2CreateOrder:
+		tx = &L2CreateOrderTxInfo{}
+	case TxTypeL2CancelOrder:
+		tx = &L2CancelOrderTxInfo{}
+	case TxTypeL2CancelAllOrders:
+		tx = &L2CancelAllOrdersTxInfo{}
+	case TxTypeL2ModifyOrder:
+		tx = &L2ModifyOrderTxInfo{}
+	case TxTypeL2MintShares:
+		tx = &L2MintSharesTxInfo{}
+	case TxTypeL2BurnShares:
+		tx = &L2BurnSharesTxInfo{}
+	case TxTypeL2UpdateLeverage:
+		tx = &L2UpdateLeverageTxInfo{}
+	case TxTypeL2CreateGroupedOrders:
+		tx = &L2CreateGroupedOrdersTxInfo{}
+	case TxTypeL2UpdateMargin:
+		tx = &L2UpdateMarginTxInfo{}
+	default:
+		return nil, fmt.Errorf("unsupported tx type %v", txType)
+	}
+
+	if err := json.Unmarshal(txInfo, tx); err != nil {
+		return nil, fmt.Errorf("failed to parse tx info. err: %w", err)
+	}
+	return tx, nil
+}
@@ -0,0 +1,71 @@
+package txtypes
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+const testChainId = 304
+
+func TestParseTxInfo(t *testing.T) {
+	sig := bytes.Repeat([]byte{7}, 80)
+	// ExpiredAt is above 2^53, so it would lose precision if decoded through a float64
+	expiredAt := int64(1<<53 + 1)
+	order := &OrderInfo{MarketIndex: 1, ClientOrderIndex: 2, BaseAmount: 1000, Price: 1000, IsAsk: 1, TimeInForce: 1, OrderExpiry: expiredAt}
+
+	txs := []TxInfo{
+		&L2ChangePubKeyTxInfo{AccountIndex: 7, ApiKeyIndex: 3, PubKey: bytes.Repeat([]byte{1}, 40), L1Sig: "0x01", ExpiredAt: expiredAt, Nonce: 1, Sig: sig},
+		&L2CreateSubAccountTxInfo{AccountIndex: 7, ApiKeyIndex: 3, ExpiredAt: expiredAt, Nonce: 1, Sig: sig},
+		&L2CreatePublicPoolTxInfo{AccountIndex: 7, ApiKeyIndex: 3, OperatorFee: 1000, InitialTotalShares: 1000, MinOperatorShareRate: 100, ExpiredAt: expiredAt, Nonce: 1, Sig: sig},
+		&L2UpdatePublicPoolTxInfo{AccountIndex: 7, ApiKeyIndex: 3, PublicPoolIndex: 100, Status: 1, OperatorFee: 1000, MinOperatorShareRate: 100, ExpiredAt: expiredAt, Nonce: 1, Sig: sig},
+		&L2TransferTxInfo{FromAccountIndex: 7, ApiKeyIndex: 3, ToAccountIndex: 8, USDCAmount: 1000000, Fee: 1, Memo: [32]byte{1, 2, 3}, L1Sig: "0x01", ExpiredAt: expiredAt, Nonce: 1, Sig: sig},
+		&L2WithdrawTxInfo{FromAccountIndex: 7, ApiKeyIndex: 3, USDCAmount: 1000000, ExpiredAt: expiredAt, Nonce: 1, Sig: sig},
+		&L2CreateOrderTxInfo{AccountIndex: 7, ApiKeyIndex: 3, OrderInfo: order, ExpiredAt: expiredAt, Nonce: 1, Sig: sig},
+		&L2CancelOrderTxInfo{AccountIndex: 7, ApiKeyIndex: 3, MarketIndex: 1, Index: 2, ExpiredAt: expiredAt, Nonce: 1, Sig: sig},
+		&L2CancelAllOrdersTxInfo{AccountIndex: 7, ApiKeyIndex: 3, TimeInForce: 1, Time: expiredAt, ExpiredAt: expiredAt, Nonce: 1, Sig: sig},
+		&L2ModifyOrderTxInfo{AccountIndex: 7, ApiKeyIndex: 3, MarketIndex: 1, Index: 2, BaseAmount: 1000, Price: 1000, TriggerPrice: 1, ExpiredAt: expiredAt, Nonce: 1, Sig: sig},
+		&L2MintSharesTxInfo{AccountIndex: 7, ApiKeyIndex: 3, PublicPoolIndex: 100, ShareAmount: 10, ExpiredAt: expiredAt, Nonce: 1, Sig: sig},
+		&L2BurnSharesTxInfo{AccountIndex: 7, ApiKeyIndex: 3, PublicPoolIndex: 100, ShareAmount: 10, ExpiredAt: expiredAt, Nonce: 1, Sig: sig},
+		&L2UpdateLeverageTxInfo{AccountIndex: 7, ApiKeyIndex: 3, MarketIndex: 1, InitialMarginFraction: 1000, MarginMode: 1, ExpiredAt: expiredAt, Nonce: 1, Sig: sig},
+		&L2CreateGroupedOrdersTxInfo{AccountIndex: 7, ApiKeyIndex: 3, GroupingType: 1, Orders: []*OrderInfo{order, order}, ExpiredAt: expiredAt, Nonce: 1, Sig: sig},
+		&L2UpdateMarginTxInfo{AccountIndex: 7, ApiKeyIndex: 3, MarketIndex: 1, USDCAmount: 1000000, Direction: 1, ExpiredAt: expiredAt, Nonce: 1, Sig: sig},
+	}
+	for _, tx := range txs {
+		txInfo, err := tx.GetTxInfo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := ParseTxInfo(tx.GetTxType(), []byte(txInfo))
+		if err != nil {
+			t.Fatalf("tx type %v: %v", tx.GetTxType(), err)
+		}
+		if !reflect.DeepEqual(parsed, tx) {
+			t.Fatalf("tx type %v: parsed %+v, expected %+v", tx.GetTxType(), parsed, tx)
+		}
+
+		hash, err := tx.Hash(testChainId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsedHash, err := parsed.Hash(testChainId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(parsedHash, hash) {
+			t.Fatalf("tx type %v: parsed tx hashes to %x instead of %x", tx.GetTxType(), parsedHash, hash)
+		}
+	}
+}
+
+func TestParseTxInfoErrors(t *testing.T) {
+	if _, err := ParseTxInfo(255, []byte(`{}`)); err == nil {
+		t.Fatal("parsed an unsupported tx type")
+	}
+	if _, err := ParseTxInfo(TxTypeL2Withdraw, []byte(`{"USDCAmount":-1}`)); err == nil {
+		t.Fatal("parsed a negative USDCAmount into a uint64")
+	}
+	if _, err := ParseTxInfo(TxTypeL2Transfer, []byte(`invalid`)); err == nil {
+		t.Fatal("parsed invalid JSON")
+	}
+}
@@ -22,10 +22,14 @@ type L2TransferTxInfo struct {
 	Fee            int64
 	Memo           [32]byte
 
+	// L1Sig is the signature of GetL1SignatureBody by the L1 address owning the account. It's not part of the hash.
+	L1Sig string `json:",omitempty"`
+
 	ExpiredAt  int64
 	Nonce      int64
 	Sig        []byte
 	SignedHash string `json:"-"`
+	DryRun     bool   `json:"-"`
 }
 
 func (txInfo *L2TransferTxInfo) Validate() error {
@@ -86,6 +90,10 @@ func (txInfo *L2TransferTxInfo) GetTxHash() string {
 	return txInfo.SignedHash
 }
 
+func (txInfo *L2TransferTxInfo) IsDryRun() bool {
+	return txInfo.DryRun
+}
+
 func (txInfo *L2TransferTxInfo) GetTxInfo() (string, error) {
 	return getTxInfo(txInfo)
 }
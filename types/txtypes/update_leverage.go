@@ -19,6 +19,7 @@ type L2UpdateLeverageTxInfo struct {
 	Nonce      int64
 	Sig        []byte
 	SignedHash string `json:"-"`
+	DryRun     bool   `json:"-"`
 }
 
 func (txInfo *L2UpdateLeverageTxInfo) GetTxType() uint8 {
@@ -33,6 +34,10 @@ func (txInfo *L2UpdateLeverageTxInfo) GetTxHash() string {
 	return txInfo.SignedHash
 }
 
+func (txInfo *L2UpdateLeverageTxInfo) IsDryRun() bool {
+	return txInfo.DryRun
+}
+
 func (txInfo *L2UpdateLeverageTxInfo) Validate() error {
 	if txInfo.AccountIndex < MinAccountIndex {
 		return ErrFromAccountIndexTooLow
@@ -0,0 +1,99 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	g "github.com/elliottech/poseidon_crypto/field/goldilocks"
+	p2 "github.com/elliottech/poseidon_crypto/hash/poseidon2_goldilocks"
+	schnorr "github.com/elliottech/poseidon_crypto/signature/schnorr"
+	ethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+// ComputeTxHash returns the hash signed by the API key, hex encoded, without needing the tx to be signed
+func ComputeTxHash(tx txtypes.TxInfo, lighterChainId uint32) (string, error) {
+	msgHash, err := tx.Hash(lighterChainId)
+	if err != nil {
+		return "", err
+	}
+	return ethCommon.Bytes2Hex(msgHash), nil
+}
+
+// VerifyTxSignature checks that sig is a signature of the tx hash by the API key with pubKey
+func VerifyTxSignature(tx txtypes.TxInfo, sig []byte, pubKey []byte, lighterChainId uint32) error {
+	msgHash, err := tx.Hash(lighterChainId)
+	if err != nil {
+		return err
+	}
+	if err := schnorr.Validate(pubKey, msgHash, sig); err != nil {
+		return fmt.Errorf("invalid signature. err: %w", err)
+	}
+	return nil
+}
+
+type AuthToken struct {
+	Deadline     time.Time
+	AccountIndex int64
+	ApiKeyIndex  uint8
+	Signature    []byte
+
+	message string
+}
+
+// ParseAuthToken splits a token made by ConstructAuthToken into its fields, without verifying it
+func ParseAuthToken(token string) (*AuthToken, error) {
+	parts := strings.Split(token, ":")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("auth token should have 4 parts separated by ':' but has %v", len(parts))
+	}
+	deadline, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deadline. err: %w", err)
+	}
+	accountIndex, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid account index. err: %w", err)
+	}
+	apiKeyIndex, err := strconv.ParseUint(parts[2], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api key index. err: %w", err)
+	}
+	signature := ethCommon.FromHex(parts[3])
+	if len(signature) == 0 {
+		return nil, fmt.Errorf("invalid signature")
+	}
+
+	return &AuthToken{
+		Deadline:     time.Unix(deadline, 0),
+		AccountIndex: accountIndex,
+		ApiKeyIndex:  uint8(apiKeyIndex),
+		Signature:    signature,
+		message:      strings.Join(parts[:3], ":"),
+	}, nil
+}
+
+// Verify checks the token was signed by the API key with pubKey and its deadline hasn't passed
+func (t *AuthToken) Verify(pubKey []byte) error {
+	msgHash, err := authTokenHash(t.message)
+	if err != nil {
+		return err
+	}
+	if err := schnorr.Validate(pubKey, msgHash, t.Signature); err != nil {
+		return fmt.Errorf("invalid signature. err: %w", err)
+	}
+	if time.Now().After(t.Deadline) {
+		return fmt.Errorf("auth token expired at %v", t.Deadline)
+	}
+	return nil
+}
+
+func authTokenHash(message string) ([]byte, error) {
+	msgInField, err := g.ArrayFromCanonicalLittleEndianBytes([]byte(message))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert bytes to field element. message: %s, error: %w", message, err)
+	}
+	return p2.HashToQuinticExtension(msgInField).ToLittleEndianBytes(), nil
+}
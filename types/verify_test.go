@@ -0,0 +1,177 @@
+package types
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	curve "github.com/elliottech/poseidon_crypto/curve/ecgfp5"
+	"github.com/uncle-gua/lighter-go/signer"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+const testChainId = 304
+
+func newTestKey(t *testing.T) signer.KeyManager {
+	t.Helper()
+	key, err := signer.NewKeyManager(curve.SampleScalar(nil).ToLittleEndianBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func newTestOpts() *TransactOpts {
+	accountIndex, apiKeyIndex, nonce := int64(7), uint8(3), int64(1)
+	return &TransactOpts{
+		FromAccountIndex: &accountIndex,
+		ApiKeyIndex:      &apiKeyIndex,
+		ExpiredAt:        time.Now().Add(time.Hour).UnixMilli(),
+		Nonce:            &nonce,
+	}
+}
+
+// signedTx is a tx along with its signature, as every tx type has its own Sig field
+type signedTx struct {
+	tx  txtypes.TxInfo
+	sig []byte
+}
+
+func signTestTxs(t *testing.T, key signer.KeyManager) []signedTx {
+	t.Helper()
+	order, err := ConstructCreateOrderTx(key, testChainId, &CreateOrderTxReq{
+		MarketIndex: 1, ClientOrderIndex: 2, BaseAmount: 1000, Price: 1000, Type: txtypes.MarketOrder, TimeInForce: txtypes.ImmediateOrCancel,
+	}, newTestOpts())
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfer, err := ConstructTransferTx(key, testChainId, &TransferTxReq{ToAccountIndex: 8, USDCAmount: 1000000, Memo: [32]byte{1}}, newTestOpts())
+	if err != nil {
+		t.Fatal(err)
+	}
+	withdraw, err := ConstructWithdrawTx(key, testChainId, &WithdrawTxReq{USDCAmount: 1000000}, newTestOpts())
+	if err != nil {
+		t.Fatal(err)
+	}
+	margin, err := ConstructUpdateMarginTx(key, testChainId, &UpdateMarginTxReq{MarketIndex: 1, USDCAmount: 1000000}, newTestOpts())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []signedTx{
+		{order, order.Sig},
+		{transfer, transfer.Sig},
+		{withdraw, withdraw.Sig},
+		{margin, margin.Sig},
+	}
+}
+
+func TestVerifyTxSignature(t *testing.T) {
+	key := newTestKey(t)
+	pubKey := key.PubKeyBytes()
+	otherPubKey := newTestKey(t).PubKeyBytes()
+
+	for _, s := range signTestTxs(t, key) {
+		txType := s.tx.GetTxType()
+		hash, err := ComputeTxHash(s.tx, testChainId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hash != s.tx.GetTxHash() {
+			t.Fatalf("tx type %v: computed hash %s, signed %s", txType, hash, s.tx.GetTxHash())
+		}
+		if err := VerifyTxSignature(s.tx, s.sig, pubKey[:], testChainId); err != nil {
+			t.Fatalf("tx type %v: %v", txType, err)
+		}
+
+		// the tx_info sent to Lighter verifies the same once parsed
+		txInfo, err := s.tx.GetTxInfo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := txtypes.ParseTxInfo(txType, []byte(txInfo))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hash, err := ComputeTxHash(parsed, testChainId); err != nil || hash != s.tx.GetTxHash() {
+			t.Fatalf("tx type %v: parsed tx hash %s, err %v", txType, hash, err)
+		}
+		if err := VerifyTxSignature(parsed, s.sig, pubKey[:], testChainId); err != nil {
+			t.Fatalf("tx type %v: parsed tx: %v", txType, err)
+		}
+
+		if err := VerifyTxSignature(s.tx, s.sig, otherPubKey[:], testChainId); err == nil {
+			t.Fatalf("tx type %v: verified with another key", txType)
+		}
+		if err := VerifyTxSignature(s.tx, s.sig, pubKey[:], testChainId+1); err == nil {
+			t.Fatalf("tx type %v: verified on another chain", txType)
+		}
+		tampered, err := txtypes.ParseTxInfo(txType, []byte(strings.Replace(txInfo, `"Nonce":1`, `"Nonce":2`, 1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyTxSignature(tampered, s.sig, pubKey[:], testChainId); err == nil {
+			t.Fatalf("tx type %v: verified a tampered tx", txType)
+		}
+	}
+}
+
+func TestAuthToken(t *testing.T) {
+	key := newTestKey(t)
+	pubKey := key.PubKeyBytes()
+	deadline := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	token, err := ConstructAuthToken(key, deadline, newTestOpts())
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseAuthToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Deadline.Equal(deadline) || parsed.AccountIndex != 7 || parsed.ApiKeyIndex != 3 {
+		t.Fatalf("unexpected token %+v", parsed)
+	}
+	if err := parsed.Verify(pubKey[:]); err != nil {
+		t.Fatal(err)
+	}
+
+	otherPubKey := newTestKey(t).PubKeyBytes()
+	if err := parsed.Verify(otherPubKey[:]); err == nil {
+		t.Fatal("verified with another key")
+	}
+	tampered, err := ParseAuthToken(strings.Replace(token, ":7:3:", ":8:3:", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tampered.Verify(pubKey[:]); err == nil {
+		t.Fatal("verified a token of another account")
+	}
+
+	expiredToken, err := ConstructAuthToken(key, time.Now().Add(-time.Minute), newTestOpts())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := ParseAuthToken(expiredToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := expired.Verify(pubKey[:]); err == nil {
+		t.Fatal("verified an expired token")
+	}
+}
+
+func TestParseAuthTokenErrors(t *testing.T) {
+	tokens := []string{
+		"",
+		"1:7:3",
+		"x:7:3:01",
+		"1:x:3:01",
+		"1:7:256:01",
+		"1:7:3:",
+	}
+	for _, token := range tokens {
+		if _, err := ParseAuthToken(token); err == nil {
+			t.Fatalf("parsed %q", token)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Signs the txs of testdata/fixtures.json with lighter.wasm and diffs txInfo, less the randomized Sig, & txHash with
+// testdata/native.json, signed by types.Construct*Tx. Run it through `just test-wasm`, which then verifies the signatures in Go.
+//
+//   node wasm/compare.mjs [build dir, defaults to ./build]
+//
+// The signed txs are written to wasm_txs.json in the build dir.
+
+import { readFileSync, writeFileSync } from "node:fs";
+import { join } from "node:path";
+import { pathToFileURL } from "node:url";
+
+const buildDir = process.argv[2] ?? "build";
+const testdata = new URL("testdata/", import.meta.url);
+
+await import(pathToFileURL(join(buildDir, "wasm_exec.js")));
+const { loadLighter } = await import("./lighter.mjs");
+const lighter = await loadLighter(readFileSync(join(buildDir, "lighter.wasm")));
+
+const fixtures = JSON.parse(readFileSync(new URL("fixtures.json", testdata), "utf8"));
+const native = JSON.parse(readFileSync(new URL("native.json", testdata), "utf8"));
+const withoutSig = (txInfo) => txInfo.replace(/,"Sig":"[^"]*"/, "");
+
+let failed = native.length !== fixtures.txs.length;
+if (failed) {
+  console.error(`native.json has ${native.length} txs but the fixtures have ${fixtures.txs.length}`);
+}
+const signed = [];
+for (const [i, fixture] of fixtures.txs.entries()) {
+  let tx;
+  try {
+    // requests are JSON strings, so the int64 values above 2^53 are passed exactly
+    tx = lighter.signTx(fixtures.config, fixture.txType, fixture.request);
+  } catch (e) {
+    console.error(`${fixture.name}: ${e.message}`);
+    failed = true;
+    continue;
+  }
+  signed.push({ name: fixture.name, txType: tx.txType, txInfo: tx.txInfo, txHash: tx.txHash });
+
+  const expected = native[i] ?? {};
+  const txInfo = withoutSig(tx.txInfo);
+  if (txInfo !== expected.txInfo) {
+    console.error(`${fixture.name}: txInfo differs\n  wasm:   ${txInfo}\n  native: ${expected.txInfo}`);
+    failed = true;
+  }
+  if (tx.txHash !== expected.txHash) {
+    console.error(`${fixture.name}: txHash differs\n  wasm:   ${tx.txHash}\n  native: ${expected.txHash}`);
+    failed = true;
+  }
+}
+
+writeFileSync(join(buildDir, "wasm_txs.json"), JSON.stringify(signed, null, 2) + "\n");
+if (failed) {
+  process.exit(1);
+}
+console.log(`${signed.length} txs match the native signer`);
+process.exit(0);
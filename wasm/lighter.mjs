@@ -0,0 +1,44 @@
+// Loads the WebAssembly build of the signer, made by `just build-wasm`, in browsers, Node & edge workers.
+// wasm_exec.js, copied next to lighter.wasm by the same recipe, must be loaded first, as it defines globalThis.Go.
+//
+//   const lighter = await loadLighter(fetch("lighter.wasm"));
+//   const { txInfo, txHash } = lighter.signTx(
+//     { privateKey, chainId: 304, accountIndex, apiKeyIndex },
+//     14, // TxTypeL2CreateOrder
+//     { MarketIndex: 0, ClientOrderIndex: 1, BaseAmount: 1000, Price: 3000, IsAsk: 0, Type: 0, TimeInForce: 1, OrderExpiry: -1, Nonce: 5 },
+//   );
+//
+// The signer has no HTTP client, so every tx request needs a Nonce.
+//
+// JS numbers only hold integers up to Number.MAX_SAFE_INTEGER (2^53 - 1) exactly, while some int64 fields go above it,
+// e.g. OrderIndex, ShareAmount or USDCAmount. Give such requests as a JSON string rather than an object, and read them from
+// txInfo, which is a JSON string as well, with a parser keeping big integers. Returned integers above the limit are strings.
+
+export async function loadLighter(source) {
+  const go = new globalThis.Go();
+  source = await source;
+  const { instance } =
+    typeof Response !== "undefined" && source instanceof Response
+      ? await WebAssembly.instantiateStreaming(source, go.importObject)
+      : await WebAssembly.instantiate(source, go.importObject);
+  // main never returns, keeping globalThis.lighter alive
+  go.run(instance);
+
+  const exports = globalThis.lighter;
+  const unwrap = (fn) => (...args) => {
+    const result = fn(...args);
+    if (result instanceof Error) {
+      throw result;
+    }
+    return result;
+  };
+  return {
+    // generateAPIKey(seed?) returns { privateKey, publicKey }
+    generateAPIKey: unwrap(exports.generateAPIKey),
+    derivePublicKey: unwrap(exports.derivePublicKey),
+    // signTx(config, txType, request) returns { txType, txInfo, txHash, messageToSign? }
+    signTx: unwrap(exports.signTx),
+    // createAuthToken(config, deadline?) takes the deadline in unix seconds, defaulting to 7 hours from now
+    createAuthToken: unwrap(exports.createAuthToken),
+  };
+}
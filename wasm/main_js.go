@@ -0,0 +1,140 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"syscall/js"
+)
+
+// main sets globalThis.lighter, whose functions return an Error instead of throwing it. lighter.mjs throws them.
+func main() {
+	js.Global().Set("lighter", js.ValueOf(map[string]any{
+		"generateAPIKey": jsFunc(func(args []js.Value) (any, error) {
+			return generateAPIKey(stringArg(args, 0))
+		}),
+		"derivePublicKey": jsFunc(func(args []js.Value) (any, error) {
+			return derivePublicKey(stringArg(args, 0))
+		}),
+		"signTx": jsFunc(func(args []js.Value) (any, error) {
+			config, err := configArg(args)
+			if err != nil {
+				return nil, err
+			}
+			if len(args) < 3 {
+				return nil, fmt.Errorf("expected (config, txType, request)")
+			}
+			return signTx(config, uint8(args[1].Int()), []byte(jsonArg(args[2])))
+		}),
+		"createAuthToken": jsFunc(func(args []js.Value) (any, error) {
+			config, err := configArg(args)
+			if err != nil {
+				return nil, err
+			}
+			deadline := int64(0)
+			if len(args) > 1 && args[1].Type() == js.TypeNumber {
+				deadline = int64(args[1].Int())
+			}
+			return createAuthToken(config, deadline)
+		}),
+	}))
+
+	// keeps the exported functions alive
+	select {}
+}
+
+// jsFunc converts the result of fn to a JS value through JSON, or to an Error.
+// Integers which a JS number can't hold exactly, above Number.MAX_SAFE_INTEGER, are converted to strings.
+func jsFunc(fn func(args []js.Value) (any, error)) js.Func {
+	return js.FuncOf(func(_ js.Value, args []js.Value) (ret any) {
+		defer func() {
+			if r := recover(); r != nil {
+				ret = jsError(fmt.Errorf("%v", r))
+			}
+		}()
+
+		result, err := fn(args)
+		if err != nil {
+			return jsError(err)
+		}
+		value, err := jsValue(result)
+		if err != nil {
+			return jsError(err)
+		}
+		return js.ValueOf(value)
+	})
+}
+
+// maxSafeInteger is Number.MAX_SAFE_INTEGER, the largest integer a JS number holds exactly
+const maxSafeInteger = 1<<53 - 1
+
+// jsValue converts v through JSON to the types accepted by js.ValueOf, keeping the integers beyond maxSafeInteger as strings
+func jsValue(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	return safeNumbers(decoded), nil
+}
+
+func safeNumbers(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			v[key] = safeNumbers(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = safeNumbers(value)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil && n >= -maxSafeInteger && n <= maxSafeInteger {
+			return n
+		}
+		if strings.ContainsAny(v.String(), ".eE") {
+			f, _ := v.Float64()
+			return f
+		}
+		return v.String()
+	}
+	return v
+}
+
+func jsError(err error) js.Value {
+	return js.Global().Get("Error").New(err.Error())
+}
+
+func stringArg(args []js.Value, i int) string {
+	if i >= len(args) || args[i].Type() != js.TypeString {
+		return ""
+	}
+	return args[i].String()
+}
+
+// jsonArg returns the argument as JSON, taking strings as already encoded
+func jsonArg(arg js.Value) string {
+	if arg.Type() == js.TypeString {
+		return arg.String()
+	}
+	return js.Global().Get("JSON").Call("stringify", arg).String()
+}
+
+func configArg(args []js.Value) (*Config, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("missing config")
+	}
+	config := &Config{}
+	if err := json.Unmarshal([]byte(jsonArg(args[0])), config); err != nil {
+		return nil, fmt.Errorf("failed to parse config. err: %w", err)
+	}
+	return config, nil
+}
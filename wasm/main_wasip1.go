@@ -0,0 +1,95 @@
+//go:build wasip1
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// request is a line read from stdin. Every request is answered with a line on stdout, holding either result or error.
+type request struct {
+	Id     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type response struct {
+	Id     json.RawMessage `json:"id,omitempty"`
+	Result any             `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+type params struct {
+	Seed       string          `json:"seed"`
+	PrivateKey string          `json:"privateKey"`
+	Config     *Config         `json:"config"`
+	TxType     uint8           `json:"txType"`
+	Request    json.RawMessage `json:"request"`
+	Deadline   int64           `json:"deadline"`
+}
+
+func main() {
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	encoder := json.NewEncoder(os.Stdout)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		req := &request{}
+		resp := &response{}
+		if err := json.Unmarshal(scanner.Bytes(), req); err != nil {
+			resp.Error = fmt.Sprintf("failed to parse request. err: %v", err)
+		} else {
+			resp.Id = req.Id
+			result, err := call(req.Method, req.Params)
+			if err != nil {
+				resp.Error = err.Error()
+			} else {
+				resp.Result = result
+			}
+		}
+		if err := encoder.Encode(resp); err != nil {
+			os.Exit(1)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func call(method string, rawParams json.RawMessage) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	p := &params{}
+	if len(rawParams) > 0 {
+		if err := json.Unmarshal(rawParams, p); err != nil {
+			return nil, fmt.Errorf("failed to parse params. err: %w", err)
+		}
+	}
+	if p.Config == nil && (method == "signTx" || method == "createAuthToken") {
+		return nil, fmt.Errorf("missing config")
+	}
+
+	switch method {
+	case "generateAPIKey":
+		return generateAPIKey(p.Seed)
+	case "derivePublicKey":
+		return derivePublicKey(p.PrivateKey)
+	case "signTx":
+		return signTx(p.Config, p.TxType, p.Request)
+	case "createAuthToken":
+		return createAuthToken(p.Config, p.Deadline)
+	default:
+		return nil, fmt.Errorf("unknown method %s", method)
+	}
+}
@@ -0,0 +1,160 @@
+//go:build !js && !wasip1
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/uncle-gua/lighter-go/client"
+	"github.com/uncle-gua/lighter-go/signer"
+	"github.com/uncle-gua/lighter-go/types"
+	"github.com/uncle-gua/lighter-go/types/txtypes"
+)
+
+// The WebAssembly build is compared with the native signer by compare.mjs: the txs of testdata/fixtures.json are signed
+// here with TxClient.SignTxJSON into testdata/native.json, which compare.mjs diffs with the output of lighter.wasm.
+// Signatures are randomized, so they're left out of the diff & verified by TestWasmSignatures instead.
+
+var update = flag.Bool("update", false, "rewrite testdata/native.json")
+
+type fixtures struct {
+	Config struct {
+		PrivateKey   string `json:"privateKey"`
+		ChainId      uint32 `json:"chainId"`
+		AccountIndex int64  `json:"accountIndex"`
+		ApiKeyIndex  uint8  `json:"apiKeyIndex"`
+	} `json:"config"`
+	// Request is a JSON string, so the int64 values above 2^53 reach lighter.wasm exactly
+	Txs []struct {
+		Name    string `json:"name"`
+		TxType  uint8  `json:"txType"`
+		Request string `json:"request"`
+	} `json:"txs"`
+}
+
+type fixtureTx struct {
+	Name   string `json:"name"`
+	TxType uint8  `json:"txType"`
+	// TxInfo is without Sig in testdata/native.json
+	TxInfo string `json:"txInfo"`
+	TxHash string `json:"txHash"`
+}
+
+var sigField = regexp.MustCompile(`,"Sig":"[^"]*"`)
+
+func withoutSig(txInfo string) string {
+	return sigField.ReplaceAllString(txInfo, "")
+}
+
+func readJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		t.Fatalf("failed to parse %s. err: %v", path, err)
+	}
+}
+
+func fixtureKey(t *testing.T, f *fixtures) signer.KeyManager {
+	t.Helper()
+	b, err := hexutil.Decode(f.Config.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := signer.NewKeyManager(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// nativeClient signs the fixtures with TxClient.SignTxJSON. Without HTTPClient, the explicit nonces of the fixtures are used.
+func nativeClient(t *testing.T, f *fixtures) *client.TxClient {
+	t.Helper()
+	txClient, err := client.NewTxClient(nil, f.Config.PrivateKey, f.Config.AccountIndex, f.Config.ApiKeyIndex, f.Config.ChainId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return txClient
+}
+
+// TestNativeFixtures checks testdata/native.json is what the native signer returns for the fixtures. Run it with -update
+// after changing the fixtures.
+func TestNativeFixtures(t *testing.T) {
+	f := &fixtures{}
+	readJSON(t, "testdata/fixtures.json", f)
+	txClient := nativeClient(t, f)
+
+	signed := make([]*fixtureTx, 0, len(f.Txs))
+	for _, fixture := range f.Txs {
+		tx, err := txClient.SignTxJSON(fixture.TxType, []byte(fixture.Request))
+		if err != nil {
+			t.Fatalf("%s: %v", fixture.Name, err)
+		}
+		txInfo, err := tx.GetTxInfo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		signed = append(signed, &fixtureTx{Name: fixture.Name, TxType: fixture.TxType, TxInfo: withoutSig(txInfo), TxHash: tx.GetTxHash()})
+	}
+
+	if *update {
+		b, err := json.MarshalIndent(signed, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile("testdata/native.json", append(b, '\n'), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected := []*fixtureTx{}
+	readJSON(t, "testdata/native.json", &expected)
+	if len(expected) != len(signed) {
+		t.Fatalf("testdata/native.json has %v txs but the fixtures have %v, run go test ./wasm -update", len(expected), len(signed))
+	}
+	for i, tx := range signed {
+		if *tx != *expected[i] {
+			t.Errorf("%s: signed %+v, testdata/native.json has %+v", tx.Name, tx, expected[i])
+		}
+	}
+}
+
+// TestWasmSignatures verifies the signatures of the txs signed by lighter.wasm, written by compare.mjs to the file
+// given by LIGHTER_WASM_TXS
+func TestWasmSignatures(t *testing.T) {
+	path := os.Getenv("LIGHTER_WASM_TXS")
+	if path == "" {
+		t.Skip("LIGHTER_WASM_TXS is not set, run just test-wasm")
+	}
+	f := &fixtures{}
+	readJSON(t, "testdata/fixtures.json", f)
+	pubKey := fixtureKey(t, f).PubKeyBytes()
+
+	wasmTxs := []*fixtureTx{}
+	readJSON(t, path, &wasmTxs)
+	if len(wasmTxs) != len(f.Txs) {
+		t.Fatalf("%s has %v txs but the fixtures have %v", path, len(wasmTxs), len(f.Txs))
+	}
+	for _, wasmTx := range wasmTxs {
+		tx, err := txtypes.ParseTxInfo(wasmTx.TxType, []byte(wasmTx.TxInfo))
+		if err != nil {
+			t.Fatalf("%s: %v", wasmTx.Name, err)
+		}
+		sig := &struct{ Sig []byte }{}
+		if err := json.Unmarshal([]byte(wasmTx.TxInfo), sig); err != nil {
+			t.Fatal(err)
+		}
+		if err := types.VerifyTxSignature(tx, sig.Sig, pubKey[:], f.Config.ChainId); err != nil {
+			t.Errorf("%s: %v", wasmTx.Name, err)
+		}
+	}
+}
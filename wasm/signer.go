@@ -0,0 +1,118 @@
+//go:build (js && wasm) || wasip1
+
+// The WebAssembly build of the signer. It only depends on the signer & types packages, so there's no HTTPClient:
+// nonces are never fetched and must be part of every tx request.
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/uncle-gua/lighter-go/signer"
+	"github.com/uncle-gua/lighter-go/types"
+)
+
+// defaultExpireTime matches the one of the client, leaving a second of margin
+const defaultExpireTime = time.Minute*10 - time.Second
+
+// Config is the API key signing the txs & auth tokens
+type Config struct {
+	PrivateKey   string `json:"privateKey"`
+	ChainId      uint32 `json:"chainId"`
+	AccountIndex int64  `json:"accountIndex"`
+	ApiKeyIndex  uint8  `json:"apiKeyIndex"`
+}
+
+type APIKey struct {
+	PrivateKey string `json:"privateKey"`
+	PublicKey  string `json:"publicKey"`
+}
+
+type SignedTx struct {
+	TxType uint8  `json:"txType"`
+	TxInfo string `json:"txInfo"`
+	TxHash string `json:"txHash"`
+	// MessageToSign is only set for the txs which also need to be signed by the L1 address of the account
+	MessageToSign string `json:"messageToSign,omitempty"`
+}
+
+func generateAPIKey(seed string) (*APIKey, error) {
+	key := signer.GenerateKeyManager(seed)
+	pubKey := key.PubKeyBytes()
+	return &APIKey{
+		PrivateKey: hexutil.Encode(key.PrvKeyBytes()),
+		PublicKey:  hexutil.Encode(pubKey[:]),
+	}, nil
+}
+
+func derivePublicKey(privateKey string) (string, error) {
+	keyManager, err := newKeyManager(privateKey)
+	if err != nil {
+		return "", err
+	}
+	pubKey := keyManager.PubKeyBytes()
+	return hexutil.Encode(pubKey[:]), nil
+}
+
+// signTx signs a tx of txType given its request as JSON, decoded by types.DecodeTxRequest: the fields of the matching types.*TxReq,
+// the Nonce & the optional ExpiredAt. ChangePubKey's PubKey & Transfer's Memo are 0x prefixed hex strings.
+func signTx(config *Config, txType uint8, request []byte) (*SignedTx, error) {
+	req, err := types.DecodeTxRequest(txType, request)
+	if err != nil {
+		return nil, err
+	}
+	if req.Nonce == nil {
+		return nil, fmt.Errorf("nonce was not provided. There's no HTTPClient in the WebAssembly build to get it from Lighter")
+	}
+	keyManager, err := newKeyManager(config.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	ops := &types.TransactOpts{
+		FromAccountIndex: &config.AccountIndex,
+		ApiKeyIndex:      &config.ApiKeyIndex,
+		ExpiredAt:        req.ExpiredAt,
+		Nonce:            req.Nonce,
+	}
+	if ops.ExpiredAt == 0 {
+		ops.ExpiredAt = time.Now().Add(defaultExpireTime).UnixMilli()
+	}
+
+	tx, err := types.ConstructTx(keyManager, config.ChainId, req, ops)
+	if err != nil {
+		return nil, err
+	}
+	txInfo, err := tx.GetTxInfo()
+	if err != nil {
+		return nil, err
+	}
+	signed := &SignedTx{TxType: tx.GetTxType(), TxInfo: txInfo, TxHash: tx.GetTxHash()}
+	if l1Tx, ok := tx.(interface{ GetL1SignatureBody() string }); ok {
+		signed.MessageToSign = l1Tx.GetL1SignatureBody()
+	}
+	return signed, nil
+}
+
+// createAuthToken creates an auth token valid until deadline (unix seconds), at most 8 hours from now. 0 defaults to 7 hours from now.
+func createAuthToken(config *Config, deadline int64) (string, error) {
+	keyManager, err := newKeyManager(config.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+	if deadline == 0 {
+		deadline = time.Now().Add(time.Hour * 7).Unix()
+	}
+	return types.ConstructAuthToken(keyManager, time.Unix(deadline, 0), &types.TransactOpts{
+		FromAccountIndex: &config.AccountIndex,
+		ApiKeyIndex:      &config.ApiKeyIndex,
+	})
+}
+
+func newKeyManager(privateKey string) (signer.KeyManager, error) {
+	b, err := types.DecodeHex(privateKey, 40)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key. err: %w", err)
+	}
+	return signer.NewKeyManager(b)
+}